@@ -0,0 +1,67 @@
+package main
+
+import (
+	"time"
+
+	"github.com/cv/t/internal/config"
+	"github.com/cv/t/internal/tzdata"
+)
+
+// loadPromptCache returns the prompt cache if it is fresh at now, with its
+// zones preloaded. Any problem reading it is a miss.
+func loadPromptCache(now time.Time) *config.PromptCache {
+	path, err := config.DefaultCachePath()
+	if err != nil {
+		return nil
+	}
+	cache, err := config.LoadPromptCache(path)
+	if err != nil || !cache.Fresh(now) {
+		return nil
+	}
+	tzdata.Preload(cache.Zones)
+	return cache
+}
+
+// newPromptCache snapshots the config files for a cache miss.
+func newPromptCache(settings *config.Settings, locations map[string]string) *config.PromptCache {
+	cache := &config.PromptCache{
+		Settings:  *settings,
+		Locations: locations,
+		Zones:     make(map[string][]byte),
+	}
+	if store, err := config.NewAliasStore(); err == nil {
+		cache.Aliases = store.List()
+	}
+	return cache
+}
+
+// savePromptCache writes the cache if it is new or this run loaded zones it
+// didn't hold. Errors are ignored: the cache only makes prompts faster.
+func savePromptCache(cache *config.PromptCache, now time.Time) {
+	changed := cache.Created.IsZero()
+	if changed {
+		cache.Created = now
+	}
+	if cache.Zones == nil {
+		cache.Zones = make(map[string][]byte)
+	}
+
+	for _, name := range tzdata.Loaded() {
+		if _, ok := cache.Zones[name]; ok {
+			continue
+		}
+		data, err := tzdata.ZoneData(name)
+		if err != nil {
+			continue
+		}
+		cache.Zones[name] = data
+		changed = true
+	}
+
+	if !changed {
+		return
+	}
+	if path, err := config.DefaultCachePath(); err == nil {
+		_ = cache.Save(path)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cv/t/internal/config"
+	"github.com/cv/t/internal/tzdata"
+)
+
+// usePromptCache points the prompt cache at a temporary directory and
+// returns the cache file's path.
+func usePromptCache(t testing.TB) string {
+	t.Helper()
+	setEnv(t, "XDG_CACHE_HOME", t.TempDir())
+	setEnv(t, "PS1_FORMAT", "")
+	path, err := config.DefaultCachePath()
+	require.NoError(t, err)
+	return path
+}
+
+func TestRun_Cached(t *testing.T) {
+	writeLocations(t, `{"HQ": "America/Chicago"}`)
+	cachePath := usePromptCache(t)
+	home := os.Getenv("HOME")
+
+	args := []string{"--cached", "--prompt", "--at", "2024-01-15T12:00Z", "hq", "sfo"}
+	var code int
+	output := captureStdout(t, func() { code = run(args) })
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "HQ 06:00 SFO 04:00", output)
+
+	cache, err := config.LoadPromptCache(cachePath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"HQ": "America/Chicago"}, cache.Locations)
+	assert.Contains(t, cache.Zones, "America/Chicago")
+	assert.Contains(t, cache.Zones, "America/Los_Angeles")
+
+	// Cached runs don't see changes to the config directory...
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(home, ".config", "t", name), []byte(content), 0o644))
+	}
+	writeFile("locations.json", `{"HQ": "Asia/Tokyo"}`)
+	output = captureStdout(t, func() { code = run(args) })
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "HQ 06:00 SFO 04:00", output)
+
+	// ...unlike uncached ones
+	output = captureStdout(t, func() { code = run(args[1:]) })
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "HQ 21:00 SFO 04:00", output)
+
+	// Saving an alias clears the cache, even from a cached run
+	captureStdout(t, func() { code = run([]string{"--cached", "--save", "team", "hq"}) })
+	assert.Equal(t, 0, code)
+	assert.NoFileExists(t, cachePath)
+	output = captureStdout(t, func() { code = run([]string{"--cached", "--prompt", "--at", "2024-01-15T12:00Z", "@team"}) })
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "HQ 21:00", output)
+
+	// Aliases come from the cache too
+	writeFile("aliases.json", `{"team": ["SFO"]}`)
+	output = captureStdout(t, func() { code = run([]string{"--cached", "--prompt", "--at", "2024-01-15T12:00Z", "@team"}) })
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "HQ 21:00", output)
+}
+
+func TestRun_CachedExpires(t *testing.T) {
+	writeLocations(t, `{"HQ": "America/Chicago"}`)
+	cachePath := usePromptCache(t)
+
+	args := []string{"--cached", "--prompt", "--at", "2024-01-15T12:00Z", "hq"}
+	var code int
+	captureStdout(t, func() { code = run(args) })
+	require.Equal(t, 0, code)
+
+	cache, err := config.LoadPromptCache(cachePath)
+	require.NoError(t, err)
+	cache.Created = time.Now().Add(-2 * config.DefaultCacheTTL)
+	require.NoError(t, cache.Save(cachePath))
+
+	writeLocations(t, `{"HQ": "Asia/Tokyo"}`)
+	output := captureStdout(t, func() { code = run(args) })
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "HQ 21:00", output)
+}
+
+func TestRun_CachedCorrupt(t *testing.T) {
+	writeLocations(t, `{"HQ": "America/Chicago"}`)
+	cachePath := usePromptCache(t)
+	require.NoError(t, os.MkdirAll(filepath.Dir(cachePath), 0o755))
+	require.NoError(t, os.WriteFile(cachePath, []byte("not json"), 0o644))
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--cached", "--prompt", "--at", "2024-01-15T12:00Z", "hq"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "HQ 06:00", output)
+
+	_, err := config.LoadPromptCache(cachePath)
+	assert.NoError(t, err, "a corrupt cache is rebuilt")
+}
+
+func TestRun_CachedSkipped(t *testing.T) {
+	writeLocations(t, `{"HQ": "America/Chicago"}`)
+	cachePath := usePromptCache(t)
+
+	// Runs with their own tz database aren't cached
+	var code int
+	captureStdout(t, func() {
+		code = run([]string{"--prompt", "--tzdata=system", "hq", "--cached"})
+	})
+	assert.Equal(t, 0, code)
+	assert.NoFileExists(t, cachePath)
+
+	// Nor are commands, which would otherwise save the snapshot they changed
+	captureStdout(t, func() {
+		code = run([]string{"--cached", "--save", "team", "hq"})
+	})
+	assert.Equal(t, 0, code)
+	assert.NoFileExists(t, cachePath)
+}
+
+// benchmarkRun measures a prompt run with aliases and custom locations,
+// as a shell prompt hook would make it.
+func benchmarkRun(b *testing.B, cached bool) {
+	home := b.TempDir()
+	setEnv(b, "HOME", home)
+	usePromptCache(b)
+	configDir := filepath.Join(home, ".config", "t")
+	require.NoError(b, os.MkdirAll(configDir, 0o755))
+	require.NoError(b, os.WriteFile(filepath.Join(configDir, "locations.json"), []byte(`{"HQ": "America/Chicago"}`), 0o644))
+	require.NoError(b, os.WriteFile(filepath.Join(configDir, "aliases.json"), []byte(`{"team": ["SFO", "LON", "BLR"]}`), 0o644))
+	require.NoError(b, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"prompt": "{code} {time}{off? zz}"}`), 0o644))
+
+	args := []string{"--prompt", "hq", "@team"}
+	if cached {
+		args = append([]string{"--cached"}, args...)
+	}
+
+	stdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	require.NoError(b, err)
+	os.Stdout = devNull
+	b.Cleanup(func() {
+		os.Stdout = stdout
+		_ = devNull.Close()
+	})
+
+	// Warm the cache
+	require.Equal(b, 0, run(args))
+
+	b.ResetTimer()
+	for b.Loop() {
+		// Forget loaded zones, as a new process would
+		require.NoError(b, tzdata.Use("system"))
+		if code := run(args); code != 0 {
+			b.Fatalf("run returned %d", code)
+		}
+	}
+}
+
+func BenchmarkRun_Prompt(b *testing.B) {
+	benchmarkRun(b, false)
+}
+
+func BenchmarkRun_PromptCached(b *testing.B) {
+	benchmarkRun(b, true)
+}
+
+func TestRun_InvalidCacheTTL(t *testing.T) {
+	writeLocations(t, `{}`)
+	configDir := filepath.Join(os.Getenv("HOME"), ".config", "t")
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"cache_ttl": "soon"}`), 0o644))
+
+	assert.Equal(t, 1, run([]string{"sfo"}))
+}
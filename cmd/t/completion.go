@@ -0,0 +1,283 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cv/t/internal/clock"
+	"github.com/cv/t/internal/config"
+)
+
+// completionShells are the shells --completion writes scripts for.
+var completionShells = map[string]string{
+	"bash": bashCompletion,
+	"zsh":  zshCompletion,
+	"fish": fishCompletion,
+}
+
+// handleCompletion writes the completion script for a shell.
+func handleCompletion(shell string) int {
+	script, ok := completionShells[shell]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unsupported shell: %s (use bash, zsh or fish)\n", shell)
+		return 1
+	}
+	fmt.Print(script)
+	return 0
+}
+
+// handleComplete writes the candidates for the last of words, the words
+// after "t" on a command line, one per line as "value\tdescription".
+// The completion scripts call it as "t --complete <words>...".
+func handleComplete(words []string, aliases *config.AliasStore) int {
+	cur, prev := "", ""
+	if len(words) > 0 {
+		cur = words[len(words)-1]
+	}
+	if len(words) > 1 {
+		prev = words[len(words)-2]
+	}
+	writeCandidates(os.Stdout, completeWord(cur, prev, aliases))
+	return 0
+}
+
+// completionCandidate is a completion and its description.
+type completionCandidate struct {
+	value       string
+	description string
+}
+
+// writeCandidates writes candidates one per line, with tab-separated
+// descriptions.
+func writeCandidates(w io.Writer, candidates []completionCandidate) {
+	for _, c := range candidates {
+		if c.description == "" {
+			_, _ = fmt.Fprintln(w, c.value)
+		} else {
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", c.value, c.description)
+		}
+	}
+}
+
+// completeWord returns the candidates for cur, given the word before it.
+func completeWord(cur, prev string, aliases *config.AliasStore) []completionCandidate {
+	if spec, ok := valueFlag(prev); ok {
+		return completeValue(spec, "", cur, aliases)
+	}
+
+	switch {
+	case strings.HasPrefix(cur, "-"):
+		return completeFlag(cur, aliases)
+	case strings.HasPrefix(cur, "@"):
+		return completeAliases("@", cur[1:], aliases)
+	case strings.Contains(cur, "@"):
+		// A conversion like SFO@9:00
+		return nil
+	default:
+		return completeCodes("", cur)
+	}
+}
+
+// valueFlag returns the spec for word if it is a flag whose value is the
+// next argument, like --at or -f.
+func valueFlag(word string) (flagSpec, bool) {
+	var spec flagSpec
+	var ok bool
+	switch {
+	case strings.HasPrefix(word, "--"):
+		spec, ok = lookupFlag(word)
+	case strings.HasPrefix(word, "-") && len(word) > 1:
+		spec, ok = lookupShortFlag(word[len(word)-1])
+	}
+	return spec, ok && spec.kind == flagRequired
+}
+
+// completeFlag returns the flags starting with cur, or the values of a
+// flag if cur is "--flag=..."
+func completeFlag(cur string, aliases *config.AliasStore) []completionCandidate {
+	if name, value, ok := strings.Cut(cur, "="); ok {
+		spec, found := lookupFlag(name)
+		if !found || spec.kind == flagBool {
+			return nil
+		}
+		return completeValue(spec, name+"=", value, aliases)
+	}
+
+	var candidates []completionCandidate
+	add := func(name, description string) {
+		if strings.HasPrefix(name, cur) {
+			candidates = append(candidates, completionCandidate{name, description})
+		}
+	}
+	for _, spec := range flagSpecs {
+		if spec.short != 0 {
+			add("-"+string(spec.short), spec.description)
+		}
+		switch spec.kind {
+		case flagBool:
+			add(spec.name, spec.description)
+		case flagOptional:
+			add(spec.name, spec.description)
+			add(spec.name+"=", spec.description)
+		case flagRequired:
+			add(spec.name+"=", spec.description)
+		}
+	}
+	return candidates
+}
+
+// completeValue returns the candidates for a flag's value, each after
+// prefix: codes, aliases or the flag's listed values.
+func completeValue(spec flagSpec, prefix, cur string, aliases *config.AliasStore) []completionCandidate {
+	switch spec.name {
+	case "--from", "--dst-list":
+		return completeCodes(prefix, cur)
+	case "--delete":
+		return completeAliases(prefix, cur, aliases)
+	}
+	if spec.values == nil {
+		return nil
+	}
+	return matchValues(prefix, cur, spec.values())
+}
+
+// matchValues returns the values starting with cur, each after prefix.
+func matchValues(prefix, cur string, values []string) []completionCandidate {
+	var candidates []completionCandidate
+	for _, value := range values {
+		if strings.HasPrefix(value, strings.ToLower(cur)) {
+			candidates = append(candidates, completionCandidate{value: prefix + value})
+		}
+	}
+	return candidates
+}
+
+// completeAliases returns the alias names starting with cur, each after
+// prefix, described by their codes.
+func completeAliases(prefix, cur string, aliases *config.AliasStore) []completionCandidate {
+	if aliases == nil {
+		store, err := config.NewAliasStore()
+		if err != nil {
+			return nil
+		}
+		aliases = store
+	}
+
+	var candidates []completionCandidate
+	for _, name := range aliases.ListSorted() {
+		if !strings.HasPrefix(name, strings.ToLower(cur)) {
+			continue
+		}
+		candidates = append(candidates, completionCandidate{prefix + name, strings.Join(aliases.Get(name), " ")})
+	}
+	return candidates
+}
+
+// completeCodes returns the codes starting with cur, each after prefix,
+// described by their airport and zone. Codes are lowercase if cur is, so
+// shells that match case-sensitively keep what was typed.
+func completeCodes(prefix, cur string) []completionCandidate {
+	lower := cur != "" && cur == strings.ToLower(cur)
+
+	infos := clock.FindByPrefix(cur)
+	candidates := make([]completionCandidate, len(infos))
+	for i, info := range infos {
+		code := info.IATA
+		if lower {
+			code = strings.ToLower(code)
+		}
+		candidates[i] = completionCandidate{prefix + code, codeDescription(info)}
+	}
+	return candidates
+}
+
+// codeDescription describes a code by its airport name and city, if
+// known, and its zone.
+func codeDescription(info clock.CodeInfo) string {
+	var place []string
+	for _, s := range []string{info.Name, info.City} {
+		if s != "" {
+			place = append(place, s)
+		}
+	}
+	if len(place) == 0 {
+		return info.Location
+	}
+	return fmt.Sprintf("%s (%s)", strings.Join(place, ", "), info.Location)
+}
+
+const bashCompletion = `# bash completion for t
+# Add to ~/.bashrc: source <(t --completion bash)
+
+_t() {
+    local line=${COMP_LINE:0:COMP_POINT}
+    local -a words
+    read -ra words <<< "$line"
+    [[ -z $line || $line == *[[:space:]] ]] && words+=("")
+
+    # Readline only replaces the text after the last word break, such as
+    # the "=" in --sort= or the "@" in @team
+    local cur=${words[${#words[@]}-1]}
+    local breaks=${COMP_WORDBREAKS//[^=@:]/}
+    local prefix=""
+    if [[ -n $breaks && $cur == *[$breaks]* ]]; then
+        prefix=${cur%"${cur##*[$breaks]}"}
+    fi
+
+    local IFS=$'\n'
+    COMPREPLY=($(t --complete "${words[@]:1}" 2>/dev/null | cut -f1))
+    COMPREPLY=("${COMPREPLY[@]#"$prefix"}")
+    if [[ ${#COMPREPLY[@]} -eq 1 && ${COMPREPLY[0]} == *= ]]; then
+        compopt -o nospace
+    fi
+}
+
+complete -F _t t
+`
+
+const zshCompletion = `#compdef t
+# zsh completion for t
+# Add to ~/.zshrc: source <(t --completion zsh)
+# or save as _t in a directory in $fpath
+
+_t() {
+    local -a values options
+    local line value description
+    for line in "${(@f)$(t --complete "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+        [[ -z $line ]] && continue
+        value=${line%%$'\t'*}
+        description=${line#*$'\t'}
+        [[ $description == "$line" ]] && description=""
+        if [[ $value == *= ]]; then
+            options+=("${value//:/\\:}:$description")
+        else
+            values+=("${value//:/\\:}:$description")
+        fi
+    done
+
+    _describe -t values 't' values
+    _describe -t options 't' options -S ''
+}
+
+if [ "$funcstack[1]" = "_t" ]; then
+    _t "$@"
+else
+    compdef _t t
+fi
+`
+
+const fishCompletion = `# fish completion for t
+# Add to ~/.config/fish/config.fish: t --completion fish | source
+# or save as ~/.config/fish/completions/t.fish
+
+function __t_complete
+    set -l words (commandline -opc)
+    set -e words[1]
+    set -l cur (commandline -ct)
+    t --complete $words "$cur" 2>/dev/null
+end
+
+complete -c t -f -a '(__t_complete)'
+`
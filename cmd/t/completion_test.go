@@ -0,0 +1,90 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cv/t/internal/clock"
+	"github.com/cv/t/internal/config"
+)
+
+func TestRun_Completion(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		var code int
+		output := captureStdout(t, func() {
+			code = run([]string{"--completion", shell})
+		})
+		assert.Equal(t, 0, code, shell)
+		assert.Contains(t, output, "t --complete", shell)
+	}
+
+	assert.Equal(t, 1, run([]string{"--completion", "tcsh"}))
+	assert.Equal(t, 1, run([]string{"--completion"}))
+}
+
+func TestRun_Complete(t *testing.T) {
+	writeLocations(t, `{"ZZHQ": "America/Chicago"}`)
+	setEnv(t, "XDG_CACHE_HOME", t.TempDir())
+	captureStdout(t, func() { run([]string{"--save", "team", "sfo", "lon"}) })
+
+	complete := func(words ...string) []string {
+		var code int
+		output := captureStdout(t, func() {
+			code = run(append([]string{"--complete"}, words...))
+		})
+		require.Equal(t, 0, code)
+		return strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	}
+
+	assert.Equal(t, []string{"--sort=\tOrder codes"}, complete("--so"))
+	assert.Equal(t, []string{"--hours=\tWork hours, e.g. 9-17 or 8:30-17:30"}, complete("--ho"))
+	assert.Equal(t, []string{"--sort=east-west"}, complete("sfo", "--sort=e"))
+	assert.Equal(t, []string{"--statusline=waybar"}, complete("--statusline=w"))
+	assert.Equal(t, []string{"@team\tSFO LON"}, complete("@t"))
+	assert.Equal(t, []string{"team\tSFO LON"}, complete("--delete", ""))
+	assert.Equal(t, []string{"ZZHQ\tAmerica/Chicago"}, complete("ZZH"))
+	assert.Equal(t, []string{"zzhq\tAmerica/Chicago"}, complete("sfo", "zzh"))
+	assert.Equal(t, []string{"--from=ZZHQ\tAmerica/Chicago"}, complete("--from=ZZH"))
+	assert.Equal(t, []string{"zsh"}, complete("--completion", "z"))
+	assert.Equal(t, []string{""}, complete("sfo@9"))
+}
+
+func TestCompleteCodes(t *testing.T) {
+	candidates := completeCodes("", "sf")
+	require.NotEmpty(t, candidates)
+	values := make([]string, len(candidates))
+	for i, c := range candidates {
+		values[i] = c.value
+		assert.True(t, strings.HasPrefix(c.value, "sf"), c.value)
+	}
+	assert.Contains(t, values, "sfo")
+	assert.IsIncreasing(t, values)
+
+	all := completeCodes("", "")
+	assert.Contains(t, all, completionCandidate{"SFO", codeDescription(clock.FindByPrefix("SFO")[0])})
+}
+
+func TestCodeDescription(t *testing.T) {
+	assert.Equal(t, "San Francisco International Airport, San Francisco (America/Los_Angeles)", codeDescription(clock.CodeInfo{
+		IATA: "SFO", Location: "America/Los_Angeles", Name: "San Francisco International Airport", City: "San Francisco",
+	}))
+	assert.Equal(t, "Stanley (Atlantic/Stanley)", codeDescription(clock.CodeInfo{IATA: "PSY", Location: "Atlantic/Stanley", City: "Stanley"}))
+	assert.Equal(t, "America/Chicago", codeDescription(clock.CodeInfo{IATA: "ZZHQ", Location: "America/Chicago"}))
+}
+
+func TestCompleteAliases(t *testing.T) {
+	store := config.NewAliasStoreFromMap(map[string][]string{
+		"team":  {"SFO", "LON"},
+		"tokyo": {"NRT"},
+		"west":  {"SFO", "LAX"},
+	})
+	assert.Equal(t, []completionCandidate{
+		{"@team", "SFO LON"},
+		{"@tokyo", "NRT"},
+	}, completeAliases("@", "T", store))
+}
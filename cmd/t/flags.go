@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cv/t/internal/clock"
+)
+
+// flagKind is whether a flag takes a value.
+type flagKind int
+
+const (
+	// flagBool takes no value: --date
+	flagBool flagKind = iota
+	// flagOptional takes a value after "=": --group or --group=offset. A
+	// numeric flag also takes a number as the next argument: --dst 7
+	flagOptional
+	// flagRequired takes a value after "=" or as the next argument:
+	// --at=T or --at T
+	flagRequired
+)
+
+// flagSpec describes a command-line flag.
+type flagSpec struct {
+	// name is the long form, e.g. "--date"
+	name string
+	// short is the single-letter form, e.g. 'd' for -d, or 0 if none
+	short byte
+	kind  flagKind
+	// arg names the value in --help, e.g. "TIME" for --at TIME
+	arg string
+	// command flags select what t does instead of showing times
+	command bool
+	// numeric optional flags take a value that is a number
+	numeric bool
+	// global flags pick the config and zone data, so every command takes them
+	global bool
+	// uses lists the other flags a command reads, such as --icons for --doctor
+	uses        []string
+	description string
+	// values lists the values shell completion offers, if any
+	values func() []string
+}
+
+// fixedValues returns a flagSpec values function for a fixed list.
+func fixedValues(values ...string) func() []string {
+	return func() []string { return values }
+}
+
+// symbolFlags pick the icons and symbols, which --doctor and --dst-list use.
+var symbolFlags = []string{"--icons", "--ascii"}
+
+// flagSpecs are the flags t accepts.
+var flagSpecs = []flagSpec{
+	{name: "--date", short: 'd', description: "Show date alongside time"},
+	{name: "--dst", kind: flagOptional, arg: "N", numeric: true, description: "Show DST warnings within 5 days, or N days"},
+	{name: "--at", kind: flagRequired, arg: "TIME", description: "Show times at an instant instead of now"},
+	{name: "--group", kind: flagOptional, arg: "MODE", description: "Show codes sharing a zone or offset on one line", values: fixedValues("zone", "offset")},
+	{name: "--sort", kind: flagRequired, arg: "ORDER", description: "Order codes", values: fixedValues("offset", "east-west", "name", "input")},
+	{name: "--from", short: 'f', kind: flagRequired, arg: "IATA", description: "Show offsets relative to a location"},
+	{name: "--12h", description: "Use a 12-hour clock"},
+	{name: "--24h", description: "Use a 24-hour clock"},
+	{name: "--layout", kind: flagRequired, arg: "LAYOUT", description: "Time layout, e.g. 'Mon 3:04PM'"},
+	{name: "--date-layout", kind: flagRequired, arg: "LAYOUT", description: "Date layout, e.g. 2006-01-02"},
+	{name: "--locale", kind: flagRequired, arg: "LANG", description: "Language for weekday and month names", values: clock.LocaleNames},
+	{name: "--relative", description: "Label dates as today, tomorrow..."},
+	{name: "--absolute", description: "Show full dates"},
+	{name: "--status", short: 's', description: "Show whether each location is working"},
+	{name: "--icons", kind: flagRequired, arg: "SET", description: "Icons shown next to times", values: fixedValues("clock", "daynight", "ascii", "none")},
+	{name: "--ascii", description: "Use only ASCII icons and symbols"},
+	{name: "--color", kind: flagOptional, arg: "WHEN", description: "When to color output", values: fixedValues("auto", "always", "never")},
+	{name: "--prompt", short: 'p', kind: flagOptional, arg: "TEMPLATE", description: "Compact output for shell prompts, optionally with a template"},
+	{name: "--prompt-sep", kind: flagRequired, arg: "SEP", description: "Separator for prompt output"},
+	{name: "--cached", global: true, description: "Read config and zones from the prompt cache"},
+	{name: "--statusline", kind: flagRequired, arg: "FORMAT", description: "Output for a status bar", values: fixedValues("tmux", "i3bar", "waybar", "polybar")},
+	{name: "--overlap", short: 'o', description: "Find overlapping work hours"},
+	{name: "--hours", kind: flagRequired, arg: "H-H", description: "Work hours, e.g. 9-17 or 8:30-17:30"},
+	{name: "--tzdata", kind: flagRequired, arg: "PATH", global: true, description: "zoneinfo directory, zip or release, e.g. 2024a"},
+	{name: "--dst-list", kind: flagRequired, arg: "IATA", command: true, uses: symbolFlags, description: "List a year's offset transitions"},
+	{name: "--in", kind: flagRequired, arg: "ZONE", command: true, description: "List the codes in an IANA zone"},
+	{name: "--country", kind: flagRequired, arg: "CC", command: true, description: "List the codes in a country"},
+	{name: "--doctor", command: true, uses: symbolFlags, description: "Report tz database problems"},
+	{name: "--save", kind: flagRequired, arg: "NAME", command: true, description: "Save codes as an alias"},
+	{name: "--list", command: true, description: "List saved aliases"},
+	{name: "--delete", kind: flagRequired, arg: "NAME", command: true, description: "Delete a saved alias"},
+	{name: "--completion", kind: flagRequired, arg: "SHELL", command: true, description: "Print a shell completion script", values: fixedValues("bash", "fish", "zsh")},
+	{name: "--version", short: 'v', command: true, description: "Show version information"},
+	{name: "--help", short: 'h', command: true, description: "Show usage"},
+}
+
+// lookupFlag returns the spec for a long flag name like "--date".
+func lookupFlag(name string) (flagSpec, bool) {
+	for _, spec := range flagSpecs {
+		if spec.name == name {
+			return spec, true
+		}
+	}
+	return flagSpec{}, false
+}
+
+// lookupShortFlag returns the spec for a short flag letter like 'd'.
+func lookupShortFlag(short byte) (flagSpec, bool) {
+	for _, spec := range flagSpecs {
+		if spec.short != 0 && spec.short == short {
+			return spec, true
+		}
+	}
+	return flagSpec{}, false
+}
+
+// parsedFlag is a flag given on the command line, by its long name.
+type parsedFlag struct {
+	spec     flagSpec
+	value    string
+	hasValue bool
+}
+
+// commandLine is a parsed command line.
+type commandLine struct {
+	// flags are in the order given, so later ones override earlier ones
+	flags []parsedFlag
+	// args are the arguments that aren't flags, such as codes
+	args []string
+}
+
+// has reports whether the flag with a long name was given.
+func (c *commandLine) has(name string) bool {
+	for _, f := range c.flags {
+		if f.spec.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// unusedFlag returns the first flag given that command doesn't use, or nil.
+// Global flags are used by every command.
+func (c *commandLine) unusedFlag(command *parsedFlag) *parsedFlag {
+	for i, f := range c.flags {
+		if !f.spec.command && !f.spec.global && !slices.Contains(command.spec.uses, f.spec.name) {
+			return &c.flags[i]
+		}
+	}
+	return nil
+}
+
+// command returns the command flag given, if any. Giving two different
+// commands is an error.
+func (c *commandLine) command() (*parsedFlag, error) {
+	var command *parsedFlag
+	for i, f := range c.flags {
+		if !f.spec.command {
+			continue
+		}
+		if command != nil && command.spec.name != f.spec.name {
+			return nil, fmt.Errorf("%s can't be combined with %s", f.spec.name, command.spec.name)
+		}
+		command = &c.flags[i]
+	}
+	return command, nil
+}
+
+// parseArgs splits a command line into flags and other arguments. Flags
+// may appear anywhere: long flags as --flag, --flag=value or --flag value,
+// and short flags alone (-d) or combined (-ds). A short flag that takes a
+// value must come last in a group, with the value attached (-flon) or next
+// (-f lon). Everything after "--" is an argument.
+//
+// An optional value goes after "=", except that --dst 7 takes the number.
+// Any other value after an optional flag, such as --group offset, is an
+// error rather than a code.
+func parseArgs(args []string) (*commandLine, error) {
+	cl := &commandLine{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			cl.args = append(cl.args, args[i+1:]...)
+			return cl, nil
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue := strings.Cut(arg, "=")
+			spec, ok := lookupFlag(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown flag: %s", name)
+			}
+			switch spec.kind {
+			case flagBool:
+				if hasValue {
+					return nil, fmt.Errorf("flag takes no value: %s", arg)
+				}
+			case flagOptional:
+				if !hasValue && i+1 < len(args) {
+					next := args[i+1]
+					switch {
+					case spec.numeric && isNumber(next):
+						i++
+						value, hasValue = next, true
+					case looksLikeValue(spec, next):
+						return nil, fmt.Errorf("flag value needs \"=\": %s=%s", name, next)
+					}
+				}
+			case flagRequired:
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("flag needs a value: %s", name)
+					}
+					i++
+					value, hasValue = args[i], true
+				}
+			}
+			cl.flags = append(cl.flags, parsedFlag{spec: spec, value: value, hasValue: hasValue})
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			for j := 1; j < len(arg); j++ {
+				spec, ok := lookupShortFlag(arg[j])
+				if !ok {
+					return nil, fmt.Errorf("unknown flag: -%c", arg[j])
+				}
+				if spec.kind != flagRequired {
+					cl.flags = append(cl.flags, parsedFlag{spec: spec})
+					continue
+				}
+
+				value := arg[j+1:]
+				if value == "" {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("flag needs a value: -%c", arg[j])
+					}
+					i++
+					value = args[i]
+				}
+				cl.flags = append(cl.flags, parsedFlag{spec: spec, value: value, hasValue: true})
+				break
+			}
+		default:
+			cl.args = append(cl.args, arg)
+		}
+	}
+	return cl, nil
+}
+
+// isNumber reports whether s is made of digits only, like 7.
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// looksLikeValue reports whether an argument after an optional flag is meant
+// as its value: one of the values it completes, or a prompt template. Codes
+// never look like either.
+func looksLikeValue(spec flagSpec, arg string) bool {
+	if strings.Contains(arg, "{") {
+		return true
+	}
+	if spec.values == nil {
+		return false
+	}
+	for _, value := range spec.values() {
+		if strings.EqualFold(arg, value) {
+			return true
+		}
+	}
+	return false
+}
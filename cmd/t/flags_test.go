@@ -0,0 +1,139 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flagNames returns the long names and values of parsed flags, as
+// "--name" or "--name=value".
+func flagNames(cl *commandLine) []string {
+	names := make([]string, len(cl.flags))
+	for i, f := range cl.flags {
+		names[i] = f.spec.name
+		if f.hasValue {
+			names[i] += "=" + f.value
+		}
+	}
+	return names
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		flags []string
+		codes []string
+	}{
+		{"flags after codes", []string{"sfo", "jfk", "-d"}, []string{"--date"}, []string{"sfo", "jfk"}},
+		{"flags between codes", []string{"sfo", "--status", "jfk"}, []string{"--status"}, []string{"sfo", "jfk"}},
+		{"value after =", []string{"--from=lon", "sfo"}, []string{"--from=lon"}, []string{"sfo"}},
+		{"value as next argument", []string{"sfo", "--from", "lon"}, []string{"--from=lon"}, []string{"sfo"}},
+		{"empty value", []string{"--prompt-sep=", "sfo"}, []string{"--prompt-sep="}, []string{"sfo"}},
+		{"optional value", []string{"--dst", "--dst=7", "sfo"}, []string{"--dst", "--dst=7"}, []string{"sfo"}},
+		{"optional value isn't the next argument", []string{"--group", "sfo"}, []string{"--group"}, []string{"sfo"}},
+		{"numeric optional value next", []string{"--dst", "3", "sfo"}, []string{"--dst=3"}, []string{"sfo"}},
+		{"numeric optional value isn't a code", []string{"--dst", "sfo"}, []string{"--dst"}, []string{"sfo"}},
+		{"combined short flags", []string{"-ds", "sfo"}, []string{"--date", "--status"}, []string{"sfo"}},
+		{"short flag value attached", []string{"-dflon", "sfo"}, []string{"--date", "--from=lon"}, []string{"sfo"}},
+		{"short flag value next", []string{"-df", "lon", "sfo"}, []string{"--date", "--from=lon"}, []string{"sfo"}},
+		{"terminator", []string{"-d", "--", "--status", "-x"}, []string{"--date"}, []string{"--status", "-x"}},
+		{"lone dash", []string{"-", "sfo"}, []string{}, []string{"-", "sfo"}},
+		{"command with flags", []string{"sfo", "--save", "team", "-d", "lon"}, []string{"--save=team", "--date"}, []string{"sfo", "lon"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, err := parseArgs(tt.args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.flags, flagNames(cl))
+			assert.Equal(t, tt.codes, cl.args)
+		})
+	}
+}
+
+func TestParseArgs_Errors(t *testing.T) {
+	tests := map[string][]string{
+		"unknown flag: --frobnicate":              {"--frobnicate", "sfo"},
+		"unknown flag: -x":                        {"-dx", "sfo"},
+		"flag needs a value: --at":                {"sfo", "--at"},
+		"flag needs a value: -f":                  {"sfo", "-f"},
+		"flag takes no value: --date=yes":         {"--date=yes", "sfo"},
+		"flag value needs \"=\": --group=offset":  {"--group", "offset", "sfo"},
+		"flag value needs \"=\": --color=never":   {"sfo", "--color", "never"},
+		"flag value needs \"=\": --prompt={code}": {"--prompt", "{code}", "sfo"},
+	}
+	for want, args := range tests {
+		_, err := parseArgs(args)
+		assert.EqualError(t, err, want)
+	}
+}
+
+func TestCommandLine_Command(t *testing.T) {
+	cl, err := parseArgs([]string{"-d", "--list"})
+	require.NoError(t, err)
+	command, err := cl.command()
+	require.NoError(t, err)
+	assert.Equal(t, "--list", command.spec.name)
+
+	cl, err = parseArgs([]string{"sfo"})
+	require.NoError(t, err)
+	command, err = cl.command()
+	require.NoError(t, err)
+	assert.Nil(t, command)
+
+	cl, err = parseArgs([]string{"--list", "--delete", "team"})
+	require.NoError(t, err)
+	_, err = cl.command()
+	assert.EqualError(t, err, "--delete can't be combined with --list")
+}
+
+func TestCommandLine_UnusedFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"global flags", []string{"--save", "team", "sfo", "--tzdata=system", "--cached"}, ""},
+		{"symbols with doctor", []string{"--doctor", "--ascii"}, ""},
+		{"icons with dst-list", []string{"--dst-list", "sfo", "--icons=emoji"}, ""},
+		{"date with save", []string{"--save", "team", "sfo", "-d"}, "--date"},
+		{"date with list", []string{"--list", "-d"}, "--date"},
+		{"12h with in", []string{"--in", "Asia/Tokyo", "--12h"}, "--12h"},
+		{"ascii with save", []string{"--save", "team", "sfo", "--ascii"}, "--ascii"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, err := parseArgs(tt.args)
+			require.NoError(t, err)
+			command, err := cl.command()
+			require.NoError(t, err)
+			require.NotNil(t, command)
+
+			f := cl.unusedFlag(command)
+			if tt.want == "" {
+				assert.Nil(t, f)
+				return
+			}
+			require.NotNil(t, f)
+			assert.Equal(t, tt.want, f.spec.name)
+		})
+	}
+}
+
+func TestFlagSpecs(t *testing.T) {
+	names := map[string]bool{}
+	shorts := map[byte]bool{}
+	for _, spec := range flagSpecs {
+		assert.False(t, names[spec.name], "duplicate flag %s", spec.name)
+		names[spec.name] = true
+		if spec.short != 0 {
+			assert.False(t, shorts[spec.short], "duplicate short flag -%c", spec.short)
+			shorts[spec.short] = true
+		}
+		assert.NotEmpty(t, spec.description, spec.name)
+	}
+}
@@ -6,11 +6,22 @@
 //	t <IATA>@<time> <IATA>...
 //	t @alias
 //	t -d | --date <IATA>...
+//	t --group[=zone|offset] <IATA>...
+//	t --sort=offset|east-west|name|input <IATA>...
+//	t --at <timestamp> <IATA>...
 //	t --overlap [--hours=H-H] <IATA> <IATA>...
+//	t --dst-list <IATA> [year]
+//	t --in <zone>
+//	t --country <CC>
+//	t --doctor
 //	t --save <name> <IATA>...
 //	t --list
 //	t --delete <name>
 //	t -v | --version
+//	t -h | --help
+//
+//	Flags may come before, after or between codes: t sfo jfk -d is the same as
+//	t -d sfo jfk.
 //
 // Examples:
 //
@@ -19,11 +30,37 @@
 //	JFK: 🕖 19:06:21 (America/New_York)
 //
 //	$ t -d sfo nrt
-//	SFO: 🕓 15:12:20 Sun Dec 28 (America/Los_Angeles)
-//	NRT: 🕘 08:12:20 Mon Dec 29 (Asia/Tokyo)
+//	SFO: 🕒 15:12:20 Sun Dec 28 (America/Los_Angeles)
+//	NRT: 🕗 08:12:20 Mon Dec 29 (Asia/Tokyo)
+//
+//	$ t --group sfo lax sjc jfk
+//	SFO/LAX/SJC: 🕓 16:06:21 (America/Los_Angeles)
+//	JFK:         🕖 19:06:21 (America/New_York)
+//
+//	$ t --sort=offset nrt lon sfo
+//	SFO: 🕓 16:06:21 (America/Los_Angeles)
+//	LON: 🕛 00:06:21 (Europe/London)
+//	NRT: 🕘 09:06:21 (Asia/Tokyo)
+//
+//	$ t --12h --date-layout=2006-01-02 -d sfo nrt
+//	SFO: 🕓 4:06:21 PM 2025-12-28 (America/Los_Angeles)
+//	NRT: 🕘 9:06:21 AM 2025-12-29 (Asia/Tokyo)
+//
+//	$ t --locale=de -d sfo nrt
+//	SFO: 🕓 16:06:21 So 28. Dez (America/Los_Angeles)
+//	NRT: 🕘 09:06:21 Mo 29. Dez (Asia/Tokyo)
+//
+//	$ t --from=lon sfo nrt
+//	SFO: 🕓 16:06:21 (-8h) (America/Los_Angeles)
+//	NRT: 🕘 09:06:21 (+9h) (Asia/Tokyo)
+//
+//	$ t --status sfo lon blr
+//	SFO: 🕘 09:06:21 (America/Los_Angeles) [working]
+//	LON: 🕔 17:06:21 (Europe/London) [off hours]
+//	BLR: 🕥 22:36:21 (Asia/Calcutta) [sleeping]
 //
 //	$ t sfo@9:00 jfk lon
-//	SFO: 🕘 09:00  →  JFK: 🕛 12:00, LON: 🕔 17:00
+//	SFO: 🕘 09:00  →  JFK: 🕛 12:00 (+3h), LON: 🕔 17:00 (+8h)
 //
 //	$ t --overlap sfo lon nrt
 //	Working hours overlap (9:00-17:00 local):
@@ -34,6 +71,22 @@
 //	  09:00-15:00 SFO = 12:00-18:00 JFK
 //	  (6 hours overlap)
 //
+//	$ t --at 2011-06-01T12:00Z sfo apw
+//	SFO: 🕔 05:00:00 Wed Jun 1 (-7h) (America/Los_Angeles)
+//	APW: 🕐 01:00:00 Wed Jun 1 (-11h) (Pacific/Apia)
+//	APW: rules differed on 2011-06-01: then -11 (UTC-11:00), today +13 (UTC+13:00)
+//	  2011-12-31 Permanent offset change (+24h, -10 → +14)
+//
+//	$ t --dst-list lon 2027
+//	LON: 2 transitions in 2027 (Europe/London)
+//	  Sun Mar 28 01:00:00 GMT +00:00 → 02:00:00 BST +01:00  DST starts (+1h)
+//	  Sun Oct 31 02:00:00 BST +01:00 → 01:00:00 GMT +00:00  DST ends (-1h)
+//
+//	$ t --in Atlantic/Stanley
+//	Atlantic/Stanley: 2 codes
+//	  MPN  (Atlantic/Stanley)
+//	  PSY  (Atlantic/Stanley)
+//
 //	$ t --save team sfo jfk lon
 //	Saved alias 'team'
 //
@@ -52,6 +105,38 @@
 //
 //	Use IATA@HH:MM to specify a time at a location and see the equivalent
 //	time in other timezones. Useful for scheduling meetings across timezones.
+//	Offsets are shown relative to the first location.
+//
+// Settings:
+//
+//	Defaults can be set in ~/.config/t/config.json; flags override them:
+//
+//	{"from": "lon"}
+//
+//	from         Location to show offsets relative to, like --from (default: local zone)
+//	12h          Use a 12-hour clock, like --12h
+//	layout       Time layout, like --layout
+//	date_layout  Date layout, like --date-layout
+//	locale       Language for weekday and month names, like --locale
+//	relative_days  Label dates as today, tomorrow and so on, like --relative
+//	color        When to color output: auto, always or never, like --color
+//	status       Show whether each location is working, like --status
+//	work_hours   Work hours, like --hours (e.g., "8-18")
+//	icons        Icons shown next to times, like --icons
+//	prompt       Template for prompt output, like --prompt=T
+//	prompt_sep   Separator for prompt output, like --prompt-sep
+//	cache_ttl    How long --cached runs reuse their snapshot (default: "1h")
+//
+// Historical Times:
+//
+//	Use --at to show times at a past (or future) instant instead of now. The
+//	timestamp is RFC 3339, optionally without seconds or zone (UTC is assumed),
+//	e.g. 1995-06-01T12:00Z or 1995-06-01. Dates are always shown. Locations
+//	whose offset or zone abbreviation on that date differs from today's
+//	because their standard offset or rules changed are flagged, along with
+//	the permanent rule changes in between, as are zones the tz database has
+//	since renamed (e.g., Europe/Kiev to Europe/Kyiv). A DST change that falls
+//	on a different date this year isn't flagged.
 //
 // Meeting Overlap:
 //
@@ -62,32 +147,114 @@
 // Aliases:
 //
 //	Save frequently used city groups with --save and recall them with @alias.
-//	Aliases are stored in ~/.config/t/aliases.json.
+//	Aliases are stored in ~/.config/t/aliases.json. They hold only codes, so
+//	display flags such as -d can't be saved with one; pass them with @alias.
+//
+// Custom Locations:
+//
+//	Define your own codes, or correct the zone of an existing one, in
+//	~/.config/t/locations.json. Entries there take precedence over the
+//	built-in IATA codes and work anywhere a code does, including IATA@HH:MM:
+//
+//	{"HQ": "America/Chicago", "BER1": "Europe/Berlin", "REMOTE-ANA": "America/Sao_Paulo"}
+//
+//	Codes start with a letter and may contain letters, digits, '-' and '_'.
 //
 // Flags:
 //
 //	-d, --date     Show date alongside time (auto-enabled when dates differ)
 //	--dst          Show DST warnings when a transition is within 5 days
-//	--dst=N        Show DST warnings when a transition is within N days
-//	--overlap      Find overlapping work hours across timezones
-//	--hours=H-H    Custom work hours for overlap calculation (default: 9-17)
+//	--dst=N, --dst N  Show DST warnings when a transition is within N days
+//	--at <time>    Show times at the given instant instead of now
+//	--group        Show codes that share a time zone on one line
+//	--group=offset Show codes whose zones currently share a UTC offset on one line
+//	-f, --from=<IATA>  Show offsets relative to a location instead of the local zone
+//	--12h, --24h   Show times on a 12-hour or 24-hour (the default) clock
+//	--layout=L     Show times with a Go time layout (e.g., --layout='Mon 3:04PM'),
+//	               including in conversions, --overlap and --ps1 output
+//	--date-layout=L  Show dates with a Go time layout (e.g., --date-layout=2006-01-02)
+//	--locale=LANG  Show weekday and month names in another language: de, en, es,
+//	               fr, it, ja, ko, nl, pt or zh
+//	--relative     Label dates as today, tomorrow, yesterday or +N days relative
+//	               to the --from location (or the source of a conversion)
+//	--absolute     Show full dates, overriding "relative_days": true
+//	-s, --status   Show whether each location is working, off hours, sleeping
+//	               (22:00-6:00) or on a weekend, based on --hours
+//	--icons=SET    Show clock (the default), daynight (sun and moon), ascii
+//	               ("*" by day, "." at night) or no icons
+//	--ascii        Use only ASCII icons and symbols, like --icons=ascii. Names
+//	               from --locale are left as they are
+//	-p, --prompt   Compact output for shell prompts, like PS1_FORMAT
+//	--prompt=T     Compact output rendering each code with a template, e.g.
+//	               --prompt='{code} {time:15:04}{dst? ⚠}{off? zz}'. Fields are
+//	               code, time[:layout], date[:layout], day, zone, abbr, offset,
+//	               icon and status; {dst? text}, {working? text}, {off? text},
+//	               {sleeping? text}, {weekend? text} and {unknown? text} show
+//	               text only when true
+//	--prompt-sep=S Separate codes in prompt output with S instead of a space
+//	--statusline=FORMAT  Output for a status bar: tmux (#[fg=...] markup),
+//	               i3bar (a JSON array of blocks), waybar (JSON for a custom
+//	               module, with the full output as its tooltip) or polybar
+//	               (%{F...} markup). Codes are rendered like --prompt, with
+//	               its template if given, and colored by work status unless
+//	               --color=never
+//	--cached       Read settings, locations, aliases and zone data from a
+//	               snapshot in the user cache directory instead of the config
+//	               directory and zoneinfo, for prompt hooks. The snapshot is
+//	               rebuilt after cache_ttl and cleared by --save and --delete
+//	--color[=WHEN] Color output: auto (the default) colors it on a terminal
+//	               unless NO_COLOR is set; also always (--color) or never.
+//	               Times outside work hours are grey and night times dimmed
+//	--sort=ORDER   Order codes by offset (west to east), east-west, name or input
+//	               (the default)
+//	--dst-list <IATA> [year]  List every offset transition in a year (default: this year)
+//	--in <zone>    List the codes mapped to an IANA zone (e.g., Asia/Kolkata)
+//	--country <CC> List the codes in a country, by ISO 3166 code (e.g., IN)
+//	--doctor       Report the tz database in use and IATA codes with zone problems
+//	-o, --overlap  Find overlapping work hours across timezones
+//	--hours=H-H    Custom work hours for overlap, --status and colored output
+//	               (default: 9-17)
+//	--tzdata=<path|version>  Load zones from a zoneinfo directory or zip, or an
+//	               IANA release installed in ~/.config/t/tzdata (e.g., 2024a)
 //	--save <name>  Save following IATA codes as named alias
 //	--list         List all saved aliases
 //	--delete <name> Delete a saved alias
+//	--completion <bash|zsh|fish>  Print a completion script for flags, codes
+//	               and @aliases
 //	-v, --version  Show version information
+//	-h, --help     Show usage and a summary of every flag
+//
+//	Flags that take a value accept --flag=value or --flag value, except
+//	--dst, --group, --color and --prompt, whose value is optional and must
+//	follow "=". Short flags combine, as in -ds; -f, the only short flag with
+//	a value, takes it attached or next (-flon or -f lon). Arguments after --
+//	are never read as flags.
+//
+// Time Zone Data:
+//
+//	Zones are loaded from $ZONEINFO or the system zoneinfo directory. Binaries
+//	built with -tags timetzdata (as release builds are) carry an embedded copy
+//	of the tz database for systems without one, such as distroless containers.
+//	Use --tzdata to check a query against a specific tz database release, and
+//	--doctor to see which tz database is in use, which codes fail to load on
+//	this machine and which use deprecated zone names (e.g., Asia/Calcutta).
 //
 // Environment:
 //
-//	PS1_FORMAT  If set, output is compact with no decorations (for shell prompts)
+//	PS1_FORMAT  If set, output is compact with no decorations (for shell prompts),
+//	            like --prompt. A value with {fields} is a prompt template.
 package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/cv/t/internal/clock"
 	"github.com/cv/t/internal/config"
+	"github.com/cv/t/internal/tzdata"
 )
 
 // Version information set by goreleaser ldflags
@@ -102,87 +269,269 @@ func main() {
 }
 
 func run(args []string) int {
-	if len(args) < 1 {
-		fmt.Fprint(os.Stderr, "usage: t [-d|--date] [--dst[=N]] [--overlap [--hours=H-H]] <IATA>...\n")
-		fmt.Fprint(os.Stderr, "       t --save <name> <IATA>...\n")
-		fmt.Fprint(os.Stderr, "       t --list | --delete <name>\n")
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
 		return 1
 	}
 
-	// Handle version flag
-	if args[0] == "-v" || args[0] == "--version" {
-		fmt.Printf("t %s (commit: %s, built: %s)\n", version, commit, date)
-		return 0
+	// --complete is given the raw words being completed, flags and all
+	complete := args[0] == "--complete"
+	cl := &commandLine{}
+	if !complete {
+		parsed, err := parseArgs(args)
+		if err != nil {
+			return usageError(err.Error())
+		}
+		cl = parsed
+	}
+	command, err := cl.command()
+	if err != nil {
+		return usageError(err.Error())
 	}
 
-	// Handle alias management flags
-	if args[0] == "--list" {
-		return handleList()
+	if command != nil {
+		// Commands don't show times, so display flags would be silently dropped
+		if f := cl.unusedFlag(command); f != nil {
+			return usageError(fmt.Sprintf("%s can't be combined with %s", f.spec.name, command.spec.name))
+		}
+
+		switch command.spec.name {
+		case "--version":
+			fmt.Printf("t %s (commit: %s, built: %s)\n", version, commit, date)
+			return 0
+		case "--help":
+			writeHelp(os.Stdout)
+			return 0
+		case "--completion":
+			return handleCompletion(command.value)
+		}
 	}
-	if args[0] == "--delete" {
-		if len(args) < 2 {
-			fmt.Fprint(os.Stderr, "usage: t --delete <name>\n")
+
+	// Commands change or report on the config directory, so they skip the cache
+	cached := cl.has("--cached") && !cl.has("--tzdata") && command == nil
+	var settings *config.Settings
+	var aliases *config.AliasStore // nil reads aliases.json when needed
+	var promptCache *config.PromptCache
+	if cached {
+		promptCache = loadPromptCache(time.Now())
+	}
+	if promptCache != nil {
+		clock.SetUserLocations(promptCache.Locations)
+		settings = &promptCache.Settings
+		aliases = config.NewAliasStoreFromMap(promptCache.Aliases)
+	} else {
+		locations, err := config.LoadLocations()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error loading locations: %v\n", err)
 			return 1
 		}
-		return handleDelete(args[1])
+		clock.SetUserLocations(locations)
+
+		settings, err = config.LoadSettings()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error loading settings: %v\n", err)
+			return 1
+		}
+
+		if cached {
+			promptCache = newPromptCache(settings, locations)
+		}
+	}
+	if cached {
+		defer savePromptCache(promptCache, time.Now())
 	}
-	if args[0] == "--save" {
-		if len(args) < 3 {
-			fmt.Fprint(os.Stderr, "usage: t --save <name> <IATA>...\n")
+	if settings.CacheTTL != "" {
+		if _, err := time.ParseDuration(settings.CacheTTL); err != nil {
+			fmt.Fprintf(os.Stderr, "error loading settings: invalid cache_ttl: %s (use a duration like 10m)\n", settings.CacheTTL)
 			return 1
 		}
-		return handleSave(args[1], args[2:])
 	}
 
-	// Parse flags
-	showDate := false
-	showDST := false
-	dstWindow := clock.DefaultDSTWindow
+	if complete {
+		return handleComplete(args[1:], aliases)
+	}
+
+	opts := clock.DisplayOptions{DSTWindow: clock.DefaultDSTWindow}
 	overlapMode := false
 	workHours := clock.DefaultWorkHours
+	var at *time.Time
+	from := settings.From
+	twelveHour := settings.TwelveHour
+	timeLayout := settings.Layout
+	dateLayout := settings.DateLayout
+	locale := settings.Locale
+	opts.RelativeDays = settings.RelativeDays
+	colorMode := clock.ColorAuto
+	if settings.Color != "" {
+		mode, err := clock.ParseColorMode(settings.Color)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error loading settings: %v\n", err)
+			return 1
+		}
+		colorMode = mode
+	}
+	opts.ShowStatus = settings.Status
+	promptMode := false
+	prompt := settings.Prompt
+	statusLine := clock.StatusLineNone
+	opts.PromptSeparator = settings.PromptSeparator
+	if ps1 := os.Getenv("PS1_FORMAT"); ps1 != "" {
+		promptMode = true
+		// PS1_FORMAT=1 keeps the compact default; a value with fields is a template
+		if strings.Contains(ps1, "{") {
+			prompt = ps1
+		}
+	}
+	if settings.WorkHours != "" {
+		parsed := clock.ParseWorkHours(settings.WorkHours)
+		if parsed == nil {
+			fmt.Fprintf(os.Stderr, "error loading settings: invalid work hours format: %s (use H-H or HH:MM-HH:MM)\n", settings.WorkHours)
+			return 1
+		}
+		workHours = *parsed
+	}
+	if settings.Icons != "" {
+		icons, err := clock.ParseIconSet(settings.Icons)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error loading settings: %v\n", err)
+			return 1
+		}
+		opts.Icons = icons
+	}
 
-	for len(args) > 0 {
-		switch {
-		case args[0] == "-d" || args[0] == "--date":
-			showDate = true
-			args = args[1:]
-		case args[0] == "--dst":
-			showDST = true
-			args = args[1:]
-		case len(args[0]) > 6 && args[0][:6] == "--dst=":
-			showDST = true
-			var n int
-			if _, err := fmt.Sscanf(args[0][6:], "%d", &n); err != nil || n < 1 {
-				fmt.Fprintf(os.Stderr, "invalid DST window: %s (use a positive number)\n", args[0][6:])
+	for _, f := range cl.flags {
+		switch f.spec.name {
+		case "--date":
+			opts.ShowDate = true
+		case "--dst":
+			opts.ShowDST = true
+			if f.hasValue {
+				var n int
+				if _, err := fmt.Sscanf(f.value, "%d", &n); err != nil || n < 1 {
+					fmt.Fprintf(os.Stderr, "invalid DST window: %s (use a positive number)\n", f.value)
+					return 1
+				}
+				opts.DSTWindow = n
+			}
+		case "--at":
+			parsed, err := clock.ParseInstant(f.value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				return 1
+			}
+			at = &parsed
+		case "--tzdata":
+			if err := tzdata.Use(f.value); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
 				return 1
 			}
-			dstWindow = n
-			args = args[1:]
-		case args[0] == "--overlap":
+		case "--group":
+			mode, err := clock.ParseGroupMode(f.value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				return 1
+			}
+			opts.Group = mode
+		case "--12h", "--24h":
+			twelveHour = f.spec.name == "--12h"
+		case "--layout":
+			timeLayout = f.value
+		case "--date-layout":
+			dateLayout = f.value
+		case "--relative", "--absolute":
+			opts.RelativeDays = f.spec.name == "--relative"
+		case "--color":
+			mode, err := clock.ParseColorMode(f.value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				return 1
+			}
+			colorMode = mode
+		case "--icons":
+			icons, err := clock.ParseIconSet(f.value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				return 1
+			}
+			opts.Icons = icons
+		case "--ascii":
+			opts.Icons = clock.IconsASCII
+		case "--prompt":
+			promptMode = true
+			if f.hasValue {
+				prompt = f.value
+			}
+		case "--prompt-sep":
+			opts.PromptSeparator = f.value
+		case "--statusline":
+			format, err := clock.ParseStatusLine(f.value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				return 1
+			}
+			statusLine = format
+		case "--status":
+			opts.ShowStatus = true
+		case "--locale":
+			locale = f.value
+		case "--from":
+			from = f.value
+		case "--sort":
+			order, err := clock.ParseSortOrder(f.value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				return 1
+			}
+			opts.Sort = order
+		case "--overlap":
 			overlapMode = true
-			args = args[1:]
-		case len(args[0]) > 8 && args[0][:8] == "--hours=":
-			hoursStr := args[0][8:]
-			if parsed := clock.ParseWorkHours(hoursStr); parsed != nil {
+		case "--hours":
+			if parsed := clock.ParseWorkHours(f.value); parsed != nil {
 				workHours = *parsed
 			} else {
-				fmt.Fprintf(os.Stderr, "invalid work hours format: %s (use H-H or HH:MM-HH:MM)\n", hoursStr)
+				fmt.Fprintf(os.Stderr, "invalid work hours format: %s (use H-H or HH:MM-HH:MM)\n", f.value)
 				return 1
 			}
-			args = args[1:]
-		default:
-			goto done
 		}
 	}
-done:
+
+	if command != nil {
+		return runCommand(*command, cl.args, opts.Icons)
+	}
+
+	args = cl.args
 
 	if len(args) == 0 {
-		fmt.Fprint(os.Stderr, "usage: t [-d|--date] [--dst[=N]] [--overlap [--hours=H-H]] <IATA>...\n")
+		return usageError("usage: t [flags] <IATA>...")
+	}
+
+	if locale != "" {
+		loc, err := clock.LookupLocale(locale)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		opts.Locale = loc
+		if dateLayout == "" {
+			dateLayout = loc.DateLayout
+		}
+	}
+
+	layouts, err := clock.NewLayouts(twelveHour, timeLayout, dateLayout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
 		return 1
 	}
+	opts.Layouts = layouts
+	opts.Color = clock.ColorEnabled(colorMode, os.Stdout)
+	if statusLine != clock.StatusLineNone {
+		// Status bars take their colors as markup, not from a terminal
+		opts.Color = colorMode != clock.ColorNever
+	}
+	opts.WorkHours = workHours
 
 	// Expand any @alias references in args
-	expandedArgs, err := expandAliases(args)
+	expandedArgs, err := expandAliasesWith(args, aliases)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		return 1
@@ -192,26 +541,167 @@ done:
 	// Handle overlap mode
 	if overlapMode {
 		if len(args) < 2 {
-			fmt.Fprint(os.Stderr, "usage: t --overlap [--hours=H-H] <IATA> <IATA>...\n")
-			return 1
+			return usageError("usage: t --overlap [--hours=H-H] <IATA> <IATA>...")
 		}
-		clock.ShowOverlap(os.Stdout, args, workHours, nil)
+		clock.ShowOverlap(os.Stdout, args, workHours, opts.Layouts, at)
 		return 0
 	}
 
-	ps1Format := os.Getenv("PS1_FORMAT") != ""
+	opts.PS1Format = promptMode
+	if (promptMode || statusLine != clock.StatusLineNone) && prompt != "" {
+		tmpl, err := clock.ParsePromptTemplate(prompt)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		opts.Prompt = tmpl
+	}
+
+	if from != "" {
+		loc, err := clock.CodeLocation(from)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --from: %v\n", err)
+			return 1
+		}
+		opts.From = loc
+	}
+
+	// A historical time is meaningless without its date
+	if at != nil {
+		opts.ShowDate = true
+	}
+
+	if statusLine != clock.StatusLineNone {
+		if clock.ParseTimeSpec(args[0]) != nil {
+			return usageError("usage: t --statusline=FORMAT <IATA>... (conversions aren't supported)")
+		}
+		clock.ShowStatusLine(os.Stdout, args, statusLine, opts, at)
+		return 0
+	}
 
 	// Check if first argument is a time spec (e.g., "SFO@9:00")
 	if spec := clock.ParseTimeSpec(args[0]); spec != nil {
 		if len(args) < 2 {
-			fmt.Fprint(os.Stderr, "usage: t <IATA>@<time> <IATA>...\n")
+			return usageError("usage: t <IATA>@<time> <IATA>...")
+		}
+		clock.ShowConversion(os.Stdout, *spec, args[1:], opts, at)
+		return 0
+	}
+
+	clock.ShowAll(os.Stdout, args, opts, at)
+	if at != nil && !opts.PS1Format {
+		clock.ShowRuleHistory(os.Stdout, args, *at, opts.Icons, nil)
+	}
+	return 0
+}
+
+// usage is printed when t is run without arguments, and by --help.
+const usage = `usage: t [flags] <IATA|@alias>...
+       t [flags] <IATA>@<time> <IATA>...
+       t [flags] --overlap <IATA> <IATA>...
+       t --dst-list <IATA> [year]
+       t --in <zone> | --country <CC>
+       t --doctor
+       t --save <name> <IATA>...
+       t --list | --delete <name>
+       t --completion bash|zsh|fish
+`
+
+// usageError reports a command-line mistake and returns the exit code.
+func usageError(msg string) int {
+	fmt.Fprintf(os.Stderr, "%s\nRun 't --help' for usage.\n", msg)
+	return 1
+}
+
+// writeHelp writes the usage and a line for each flag.
+func writeHelp(w io.Writer) {
+	_, _ = fmt.Fprint(w, usage)
+	_, _ = fmt.Fprint(w, "\nFlags may come before or after codes, as --flag=value or --flag value.\n")
+	_, _ = fmt.Fprint(w, "Optional values, shown as [=VALUE], need the =, except for --dst N.\n")
+	_, _ = fmt.Fprint(w, "Short flags can be combined, e.g. -ds. Arguments after -- are never flags.\n\n")
+
+	for _, spec := range flagSpecs {
+		name := spec.name
+		switch spec.kind {
+		case flagOptional:
+			name += "[=" + spec.arg + "]"
+		case flagRequired:
+			name += " " + spec.arg
+		}
+		if spec.short != 0 {
+			name = fmt.Sprintf("-%c, %s", spec.short, name)
+		} else {
+			name = "    " + name
+		}
+
+		description := spec.description
+		if spec.values != nil {
+			description += ": " + strings.Join(spec.values(), ", ")
+		}
+		_, _ = fmt.Fprintf(w, "  %-26s %s\n", name, description)
+	}
+}
+
+// runCommand runs a command flag such as --save, given the arguments that
+// aren't flags. icons picks the symbols --doctor and --dst-list use.
+func runCommand(command parsedFlag, args []string, icons clock.IconSet) int {
+	switch command.spec.name {
+	case "--doctor":
+		if len(args) > 0 {
+			return usageError("usage: t --doctor")
+		}
+		if !clock.ShowDoctor(os.Stdout, icons) {
 			return 1
 		}
-		clock.ShowConversion(os.Stdout, *spec, args[1:], ps1Format, nil)
 		return 0
+	case "--list":
+		if len(args) > 0 {
+			return usageError("usage: t --list")
+		}
+		return handleList()
+	case "--delete":
+		if len(args) > 0 {
+			return usageError("usage: t --delete <name>")
+		}
+		return handleDelete(command.value)
+	case "--save":
+		if len(args) == 0 {
+			return usageError("usage: t --save <name> <IATA>...")
+		}
+		return handleSave(command.value, args)
+	case "--in":
+		if len(args) > 0 {
+			return usageError("usage: t --in <zone>")
+		}
+		clock.ShowZone(os.Stdout, command.value)
+		return 0
+	case "--country":
+		if len(args) > 0 {
+			return usageError("usage: t --country <CC>")
+		}
+		clock.ShowCountry(os.Stdout, command.value)
+		return 0
+	case "--dst-list":
+		if len(args) > 1 {
+			return usageError("usage: t --dst-list <IATA> [year]")
+		}
+		return handleDSTList(append([]string{command.value}, args...), icons)
+	}
+	return usageError("unknown command: " + command.spec.name)
+}
+
+// handleDSTList lists the offset transitions for an IATA code in a year.
+// args is the IATA code optionally followed by a year; the default is this year.
+func handleDSTList(args []string, icons clock.IconSet) int {
+	year := time.Now().Year()
+	if len(args) > 1 {
+		if _, err := fmt.Sscanf(args[1], "%d", &year); err != nil || year < 1 {
+			fmt.Fprintf(os.Stderr, "invalid year: %s\n", args[1])
+			return 1
+		}
 	}
 
-	clock.ShowAllWithDST(os.Stdout, args, ps1Format, showDate, showDST, dstWindow, nil)
+	clock.ShowDSTList(os.Stdout, args[0], year, icons)
 	return 0
 }
 
@@ -227,6 +717,7 @@ func handleSave(name string, codes []string) int {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		return 1
 	}
+	_ = config.ClearPromptCache()
 
 	fmt.Printf("Saved alias '%s'\n", name)
 	return 0
@@ -266,6 +757,7 @@ func handleDelete(name string) int {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		return 1
 	}
+	_ = config.ClearPromptCache()
 
 	fmt.Printf("Deleted alias '%s'\n", name)
 	return 0
@@ -274,8 +766,14 @@ func handleDelete(name string) int {
 // expandAliases expands any @alias references in the argument list.
 // Returns the expanded list of IATA codes.
 func expandAliases(args []string) ([]string, error) {
+	return expandAliasesWith(args, nil)
+}
+
+// expandAliasesWith is expandAliases with the aliases in store, e.g. from
+// the prompt cache. A nil store reads aliases.json if there are any @alias
+// references.
+func expandAliasesWith(args []string, store *config.AliasStore) ([]string, error) {
 	var result []string
-	var store *config.AliasStore
 
 	for _, arg := range args {
 		if strings.HasPrefix(arg, "@") {
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/cv/t/internal/config"
+	"github.com/cv/t/internal/tzdata"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,7 +34,7 @@ func captureStdout(t *testing.T, f func()) string {
 }
 
 // setEnv sets an environment variable and returns a cleanup function.
-func setEnv(t *testing.T, key, value string) {
+func setEnv(t testing.TB, key, value string) {
 	t.Helper()
 	orig := os.Getenv(key)
 	require.NoError(t, os.Setenv(key, value))
@@ -43,11 +44,15 @@ func setEnv(t *testing.T, key, value string) {
 }
 
 func TestRun_NoArgs(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	code := run([]string{})
 	assert.Equal(t, 1, code)
 }
 
 func TestRun_Version(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	var code int
 	output := captureStdout(t, func() {
 		code = run([]string{"-v"})
@@ -58,6 +63,8 @@ func TestRun_Version(t *testing.T) {
 }
 
 func TestRun_VersionLong(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	var code int
 	_ = captureStdout(t, func() {
 		code = run([]string{"--version"})
@@ -67,6 +74,8 @@ func TestRun_VersionLong(t *testing.T) {
 }
 
 func TestRun_BasicIATA(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	var code int
 	output := captureStdout(t, func() {
 		code = run([]string{"sfo"})
@@ -78,6 +87,8 @@ func TestRun_BasicIATA(t *testing.T) {
 }
 
 func TestRun_MultipleIATA(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	var code int
 	output := captureStdout(t, func() {
 		code = run([]string{"sfo", "jfk"})
@@ -89,6 +100,8 @@ func TestRun_MultipleIATA(t *testing.T) {
 }
 
 func TestRun_DateFlag(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	var code int
 	output := captureStdout(t, func() {
 		code = run([]string{"-d", "sfo"})
@@ -142,6 +155,8 @@ func TestRun_SaveListDelete(t *testing.T) {
 }
 
 func TestRun_SaveMissingArgs(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	code := run([]string{"--save"})
 	assert.Equal(t, 1, code)
 
@@ -149,7 +164,37 @@ func TestRun_SaveMissingArgs(t *testing.T) {
 	assert.Equal(t, 1, code)
 }
 
+func TestRun_SaveDisplayFlags(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	assert.Equal(t, 1, run([]string{"--save", "team", "sfo", "-d"}), "aliases can't hold display flags")
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--list"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "No aliases saved")
+}
+
+func TestRun_CommandDisplayFlags(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	assert.Equal(t, 1, run([]string{"--list", "-d"}))
+	assert.Equal(t, 1, run([]string{"--in", "Asia/Tokyo", "--12h"}))
+	assert.Equal(t, 1, run([]string{"--version", "--utc"}))
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--doctor", "--ascii"})
+	})
+	assert.Equal(t, 0, code)
+	assert.NotEmpty(t, output)
+}
+
 func TestRun_DeleteMissingArg(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	code := run([]string{"--delete"})
 	assert.Equal(t, 1, code)
 }
@@ -213,6 +258,8 @@ func TestRun_MixedAliasAndIATA(t *testing.T) {
 }
 
 func TestRun_TimeConversion(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	var code int
 	output := captureStdout(t, func() {
 		code = run([]string{"sfo@9:00", "jfk"})
@@ -225,11 +272,15 @@ func TestRun_TimeConversion(t *testing.T) {
 }
 
 func TestRun_TimeConversionMissingTarget(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	code := run([]string{"sfo@9:00"})
 	assert.Equal(t, 1, code)
 }
 
 func TestRun_Overlap(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	var code int
 	output := captureStdout(t, func() {
 		code = run([]string{"--overlap", "sfo", "jfk"})
@@ -240,11 +291,15 @@ func TestRun_Overlap(t *testing.T) {
 }
 
 func TestRun_OverlapMissingLocations(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	code := run([]string{"--overlap", "sfo"})
 	assert.Equal(t, 1, code)
 }
 
 func TestRun_OverlapWithHours(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	var code int
 	output := captureStdout(t, func() {
 		code = run([]string{"--overlap", "--hours=8-18", "sfo", "jfk"})
@@ -255,6 +310,8 @@ func TestRun_OverlapWithHours(t *testing.T) {
 }
 
 func TestRun_InvalidHoursFormat(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	code := run([]string{"--hours=invalid", "sfo", "jfk"})
 	assert.Equal(t, 1, code)
 }
@@ -315,6 +372,8 @@ func TestExpandAliases(t *testing.T) {
 }
 
 func TestRun_EmptyArgsAfterFlags(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	code := run([]string{"-d"})
 	assert.Equal(t, 1, code)
 
@@ -323,6 +382,8 @@ func TestRun_EmptyArgsAfterFlags(t *testing.T) {
 }
 
 func TestRun_PS1Format(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	setEnv(t, "PS1_FORMAT", "1")
 
 	var code int
@@ -339,6 +400,8 @@ func TestRun_PS1Format(t *testing.T) {
 }
 
 func TestRun_DSTFlag(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	var code int
 	output := captureStdout(t, func() {
 		code = run([]string{"--dst", "sfo"})
@@ -350,6 +413,8 @@ func TestRun_DSTFlag(t *testing.T) {
 }
 
 func TestRun_DSTFlagWithWindow(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	var code int
 	output := captureStdout(t, func() {
 		code = run([]string{"--dst=10", "sfo"})
@@ -357,9 +422,19 @@ func TestRun_DSTFlagWithWindow(t *testing.T) {
 
 	assert.Equal(t, 0, code)
 	assert.Contains(t, output, "SFO:")
+
+	// The window can also be the next argument
+	output = captureStdout(t, func() {
+		code = run([]string{"--at", "2024-03-04T18:00Z", "--dst", "7", "sfo"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "DST starts in 6 days")
+	assert.NotContains(t, output, "Unknown", "7 is the window, not a code")
 }
 
 func TestRun_DSTFlagInvalidWindow(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
 	code := run([]string{"--dst=invalid", "sfo"})
 	assert.Equal(t, 1, code)
 
@@ -369,3 +444,558 @@ func TestRun_DSTFlagInvalidWindow(t *testing.T) {
 	code = run([]string{"--dst=-5", "sfo"})
 	assert.Equal(t, 1, code)
 }
+
+func TestRun_DSTList(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--dst-list", "lon", "2027"})
+	})
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "LON: 2 transitions in 2027")
+}
+
+func TestRun_DSTListInvalid(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	code := run([]string{"--dst-list"})
+	assert.Equal(t, 1, code)
+
+	code = run([]string{"--dst-list", "lon", "next"})
+	assert.Equal(t, 1, code)
+
+	code = run([]string{"--dst-list", "lon", "2027", "extra"})
+	assert.Equal(t, 1, code)
+}
+
+func TestRun_At(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--at", "2011-06-01T12:00Z", "sfo", "apw"})
+	})
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "SFO: 🕔 05:00:00 Wed Jun 1")
+	assert.Contains(t, output, "APW: rules differed on 2011-06-01")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--at=2011-06-01T12:00Z", "sfo@9:00", "jfk"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "JFK: 🕛 12:00")
+}
+
+func TestRun_AtInvalid(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	code := run([]string{"--at", "yesterday", "sfo"})
+	assert.Equal(t, 1, code)
+
+	code = run([]string{"--at"})
+	assert.Equal(t, 1, code)
+}
+
+func TestRun_TZData(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	zoneinfo := "/usr/share/zoneinfo"
+	if _, err := os.Stat(zoneinfo); err != nil {
+		t.Skip("system zoneinfo is not available")
+	}
+	t.Cleanup(func() { _ = tzdata.Use("system") })
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--tzdata=" + zoneinfo, "sfo"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "America/Los_Angeles")
+
+	// An empty zoneinfo directory explains why a known code can't be shown
+	output = captureStdout(t, func() {
+		code = run([]string{"--tzdata=" + t.TempDir(), "sfo"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "SFO: ??:??:?? (loading location America/Los_Angeles")
+}
+
+func TestRun_TZDataInvalid(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	code := run([]string{"--tzdata=/nonexistent/zoneinfo", "sfo"})
+	assert.Equal(t, 1, code)
+}
+
+func TestRun_Doctor(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--doctor"})
+	})
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "tzdata source:")
+	assert.Contains(t, output, "all zones load")
+}
+
+// writeLocations writes locations.json in the test's config directory.
+func writeLocations(t *testing.T, content string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	setEnv(t, "HOME", tmpDir)
+
+	configDir := filepath.Join(tmpDir, ".config", "t")
+	require.NoError(t, os.MkdirAll(configDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "locations.json"), []byte(content), 0o644))
+}
+
+func TestRun_CustomLocations(t *testing.T) {
+	writeLocations(t, `{"HQ": "America/Chicago", "BER1": "Europe/Berlin", "SFO": "Asia/Tokyo"}`)
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"hq", "BER1", "sfo"})
+	})
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "HQ:")
+	assert.Contains(t, output, "(America/Chicago)")
+	assert.Contains(t, output, "(Europe/Berlin)")
+	assert.Contains(t, output, "SFO:")
+	assert.Contains(t, output, "(Asia/Tokyo)", "user-defined entries override IATA codes")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"ber1@9:00", "hq"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "BER1:")
+	assert.Contains(t, output, "HQ:")
+}
+
+func TestRun_CustomLocationsInvalid(t *testing.T) {
+	writeLocations(t, `{"HQ": `)
+
+	code := run([]string{"sfo"})
+	assert.Equal(t, 1, code)
+}
+
+func TestRun_ReverseLookup(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--in", "Atlantic/Stanley"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "Atlantic/Stanley: 2 codes")
+	assert.Contains(t, output, "PSY")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--country", "fk"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "FK (Falkland Islands): 2 codes")
+}
+
+func TestRun_ReverseLookupMissingArg(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	assert.Equal(t, 1, run([]string{"--in"}))
+	assert.Equal(t, 1, run([]string{"--country"}))
+	assert.Equal(t, 1, run([]string{"--country", "FK", "IN"}))
+}
+
+func TestRun_Group(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--group", "sfo", "lax", "jfk"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "SFO/LAX: ")
+	assert.Contains(t, output, "JFK: ")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--group=offset", "--at", "2024-06-15T12:00Z", "lhr", "los"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "LHR/LOS: ")
+	assert.Contains(t, output, "(Europe/London, Africa/Lagos)")
+}
+
+func TestRun_GroupInvalid(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	code := run([]string{"--group=country", "sfo"})
+	assert.Equal(t, 1, code)
+}
+
+func TestRun_Sort(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--sort=offset", "nrt", "lhr", "sfo"})
+	})
+	assert.Equal(t, 0, code)
+
+	sfo := strings.Index(output, "SFO:")
+	lhr := strings.Index(output, "LHR:")
+	nrt := strings.Index(output, "NRT:")
+	assert.True(t, sfo < lhr && lhr < nrt, "expected west-to-east order, got:\n%s", output)
+}
+
+func TestRun_SortInvalid(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	code := run([]string{"--sort=zone", "sfo"})
+	assert.Equal(t, 1, code)
+}
+
+func TestRun_From(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--from=nrt", "--at", "2024-01-15T12:00Z", "lhr"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "(-9h) (Europe/London)")
+
+	assert.Equal(t, 1, run([]string{"--from=xyzq", "lhr"}))
+}
+
+func TestRun_FromSetting(t *testing.T) {
+	tmpDir := t.TempDir()
+	setEnv(t, "HOME", tmpDir)
+	configDir := filepath.Join(tmpDir, ".config", "t")
+	require.NoError(t, os.MkdirAll(configDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"from": "nrt"}`), 0o644))
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--at", "2024-01-15T12:00Z", "lhr"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "(-9h) (Europe/London)")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--from=lhr", "--at", "2024-01-15T12:00Z", "lhr"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "(+0h) (Europe/London)", "flags override settings")
+}
+
+func TestRun_InvalidSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	setEnv(t, "HOME", tmpDir)
+	configDir := filepath.Join(tmpDir, ".config", "t")
+	require.NoError(t, os.MkdirAll(configDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"frm": "nrt"}`), 0o644))
+
+	assert.Equal(t, 1, run([]string{"lhr"}))
+}
+
+func TestRun_Layouts(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--12h", "--date-layout=2006-01-02", "--at", "2024-01-15T23:06:21Z", "sfo"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "3:06:21 PM 2024-01-15")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--layout=15h04", "--at", "2024-01-15T12:00Z", "sfo@9", "jfk"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "SFO: 🕘 09h00  →  JFK: 🕛 12h00")
+
+	assert.Equal(t, 1, run([]string{"--layout=hh:mm", "sfo"}))
+}
+
+func TestRun_Locale(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--locale=de", "--at", "2024-01-15T23:06:21Z", "sfo"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "15:06:21 Mo 15. Jan")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--locale=pt_BR.UTF-8", "--date-layout=Monday", "--at", "2024-01-15T23:06:21Z", "sfo"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "15:06:21 segunda-feira")
+
+	assert.Equal(t, 1, run([]string{"--locale=xx", "sfo"}))
+}
+
+func TestRun_RelativeDays(t *testing.T) {
+	tmpDir := t.TempDir()
+	setEnv(t, "HOME", tmpDir)
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--relative", "--at", "2024-01-15T12:00Z", "sfo@20:00", "nrt"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "NRT: 🕐 13:00 tomorrow (+17h)")
+
+	configDir := filepath.Join(tmpDir, ".config", "t")
+	require.NoError(t, os.MkdirAll(configDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"relative_days": true, "locale": "fr"}`), 0o644))
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--from=sfo", "--at", "2024-01-15T05:00Z", "sfo", "nrt"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "21:00:00 aujourd'hui")
+	assert.Contains(t, output, "14:00:00 demain")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--absolute", "--at", "2024-01-15T05:00Z", "nrt"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "14:00:00 lun. 15 janv.")
+}
+
+func TestRun_Color(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--at", "2024-01-15T05:00Z", "xyz"})
+	})
+	assert.Equal(t, 0, code)
+	assert.NotContains(t, output, "\x1b[", "no color when stdout isn't a terminal")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--color=always", "--at", "2024-01-15T05:00Z", "xyz"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "\x1b[31mXYZ")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--color", "--hours=12-22", "--at", "2024-01-16T05:00Z", "sfo"})
+	})
+	assert.Equal(t, 0, code)
+	assert.NotContains(t, output, "\x1b[")
+
+	assert.Equal(t, 1, run([]string{"--color=sometimes", "sfo"}))
+}
+
+func TestRun_Status(t *testing.T) {
+	tmpDir := t.TempDir()
+	setEnv(t, "HOME", tmpDir)
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--status", "--hours=10-18", "--at", "2024-01-15T17:06:21Z", "sfo", "lon"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "(America/Los_Angeles) [off hours]")
+	assert.Contains(t, output, "(Europe/London) [working]")
+
+	configDir := filepath.Join(tmpDir, ".config", "t")
+	require.NoError(t, os.MkdirAll(configDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"status": true, "work_hours": "8-9"}`), 0o644))
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--at", "2024-01-15T17:06:21Z", "sfo"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "(America/Los_Angeles) [off hours]")
+
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"work_hours": "nine to five"}`), 0o644))
+	assert.Equal(t, 1, run([]string{"sfo"}))
+}
+
+func TestRun_Icons(t *testing.T) {
+	tmpDir := t.TempDir()
+	setEnv(t, "HOME", tmpDir)
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--ascii", "--at", "2024-01-15T12:00Z", "sfo@20:00", "nrt"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "SFO: . 20:00 Mon Jan 15  ->  NRT: * 13:00 Tue Jan 16 (+17h)")
+
+	configDir := filepath.Join(tmpDir, ".config", "t")
+	require.NoError(t, os.MkdirAll(configDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"icons": "none"}`), 0o644))
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--at", "2024-01-15T12:00Z", "sfo@9:00", "jfk"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "SFO: 09:00  →  JFK: 12:00 (+3h)")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--icons=daynight", "--at", "2024-01-15T12:00Z", "sfo@9:00", "jfk"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "SFO: 🌞 09:00  →  JFK: 🌞 12:00 (+3h)")
+
+	assert.Equal(t, 1, run([]string{"--icons=emoji", "sfo"}))
+}
+
+func TestRun_Prompt(t *testing.T) {
+	tmpDir := t.TempDir()
+	setEnv(t, "HOME", tmpDir)
+	setEnv(t, "PS1_FORMAT", "")
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--prompt", "--at", "2024-03-04T18:00Z", "sfo", "nrt"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "SFO 10:00 NRT 03:00", output)
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--prompt={code}{off? zz}", "--prompt-sep=|", "--at", "2024-03-04T18:00Z", "sfo", "nrt"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "SFO|NRTzz", output)
+
+	setEnv(t, "PS1_FORMAT", "{code}={time:15}")
+	output = captureStdout(t, func() {
+		code = run([]string{"--at", "2024-03-04T18:00Z", "sfo", "nrt"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "SFO=10 NRT=03", output)
+
+	configDir := filepath.Join(tmpDir, ".config", "t")
+	require.NoError(t, os.MkdirAll(configDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"prompt": "[{code}]", "prompt_sep": ""}`), 0o644))
+	setEnv(t, "PS1_FORMAT", "1")
+	output = captureStdout(t, func() {
+		code = run([]string{"--at", "2024-03-04T18:00Z", "sfo", "nrt"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "[SFO] [NRT]", output)
+
+	assert.Equal(t, 1, run([]string{"--prompt={nope}", "sfo"}))
+}
+
+func TestRun_StatusLine(t *testing.T) {
+	tmpDir := t.TempDir()
+	setEnv(t, "HOME", tmpDir)
+	setEnv(t, "PS1_FORMAT", "")
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--statusline=tmux", "--at", "2024-03-04T18:00Z", "sfo", "nrt"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "SFO 10:00 #[fg=#5c5c5c]NRT 03:00#[default]\n", output, "status bars are colored when piped")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--statusline=polybar", "--color=never", "--prompt={code}", "--prompt-sep=,", "--at", "2024-03-04T18:00Z", "sfo", "nrt"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "SFO,NRT\n", output)
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--statusline=waybar", "--at", "2024-03-04T18:00Z", "sfo"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, `"tooltip":"SFO: 🕙 10:00:00`)
+
+	assert.Equal(t, 1, run([]string{"--statusline=dzen", "sfo"}))
+	assert.Equal(t, 1, run([]string{"--statusline=tmux", "sfo@9:00", "nrt"}))
+}
+
+func TestRun_LayoutSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	setEnv(t, "HOME", tmpDir)
+	configDir := filepath.Join(tmpDir, ".config", "t")
+	require.NoError(t, os.MkdirAll(configDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"12h": true}`), 0o644))
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"--at", "2024-01-15T23:06:21Z", "sfo"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "3:06:21 PM")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--24h", "--at", "2024-01-15T23:06:21Z", "sfo"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "15:06:21")
+}
+
+func TestRun_FlagsAnywhere(t *testing.T) {
+	tmpDir := t.TempDir()
+	setEnv(t, "HOME", tmpDir)
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"sfo", "nrt", "-d", "--at", "2024-01-15T12:00Z", "--from", "lon"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "SFO: 🕓 04:00:00 Mon Jan 15 (-8h) (America/Los_Angeles)")
+	assert.Contains(t, output, "NRT: 🕘 21:00:00 Mon Jan 15 (+9h) (Asia/Tokyo)")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"-sd", "sfo", "--", "--at"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "--AT: ??:??:?? (Unknown)", "arguments after -- are codes")
+
+	// Commands accept flags too
+	output = captureStdout(t, func() {
+		code = run([]string{"sfo", "--save", "team", "lon", "--cached"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "Saved alias 'team'")
+
+	output = captureStdout(t, func() {
+		code = run([]string{"--tzdata=system", "--list"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "team: SFO LON\n", output)
+}
+
+func TestRun_UsageErrors(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	tests := [][]string{
+		{"--frobnicate", "sfo"},
+		{"sfo", "--at"},
+		{"--date=yes", "sfo"},
+		{"--list", "--delete", "team"},
+		{"--list", "sfo"},
+		{"--doctor", "sfo"},
+		{"--delete", "team", "sfo"},
+	}
+	for _, args := range tests {
+		assert.Equal(t, 1, run(args), args)
+	}
+}
+
+func TestRun_Help(t *testing.T) {
+	setEnv(t, "HOME", t.TempDir())
+
+	var code int
+	output := captureStdout(t, func() {
+		code = run([]string{"-h"})
+	})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, "usage: t [flags] <IATA|@alias>...")
+	assert.Contains(t, output, "-d, --date")
+	assert.Contains(t, output, "--at TIME")
+	assert.Contains(t, output, "--dst[=N]")
+}
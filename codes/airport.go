@@ -0,0 +1,10 @@
+package codes
+
+// Airport describes the place an IATA code refers to.
+type Airport struct {
+	Name string
+	City string
+	// Country is an ISO 3166-1 alpha-2 code, or "" if the dataset's country
+	// name couldn't be mapped to one
+	Country string
+}
@@ -0,0 +1,22 @@
+package codes
+
+import "testing"
+
+func TestAirports(t *testing.T) {
+	if len(Airports) == 0 {
+		t.Skip("airports.go has not been generated yet; run go generate ./codes/...")
+	}
+
+	sfo, ok := Airports["SFO"]
+	if !ok || sfo.Name == "" {
+		t.Fatalf("Airports[%q] = %+v, want a name", "SFO", sfo)
+	}
+	if sfo.Country != "US" {
+		t.Errorf("Airports[%q].Country = %q, want %q", "SFO", sfo.Country, "US")
+	}
+	for code := range Airports {
+		if _, ok := IATA[code]; !ok {
+			t.Errorf("Airports has %s, which IATA doesn't", code)
+		}
+	}
+}
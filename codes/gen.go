@@ -1,15 +1,32 @@
 //go:build ignore
 
 // This program generates iata.go by downloading airport data from OpenFlights
-// and mapping each IATA code to its IANA timezone.
+// and mapping each IATA code to its IANA timezone, and airports.go with each
+// code's name, city and country.
 //
 // Usage: go generate ./codes/...
+//
+// To generate without network access, pass a local copy of the dataset, or "-"
+// to read it from stdin:
+//
+//	go run gen.go -in airports-extended.dat -countries countries.dat
+//
+// Other datasets can be merged in to cross-check OpenFlights. Each flag takes a
+// file or URL; -unlocode takes a comma-separated list, since UN/LOCODE is
+// published in several parts; OurAirports is downloaded by default when -in
+// is a URL. Only OpenFlights has zones, so -precedence decides names, cities
+// and countries: for each code, the first dataset in it that has one wins.
+// A code whose OpenFlights record loses its country to another dataset is
+// left out of iata.go, since its zone is likely wrong too.
+// Disagreements are written to the -conflicts report (or stderr):
+//
+//	go run gen.go -ourairports airports.csv -unlocode part1.csv,part2.csv,part3.csv \
+//		-conflicts conflicts.txt
 package main
 
 import (
-	"bufio"
 	"bytes"
-	"encoding/csv"
+	"flag"
 	"fmt"
 	"go/format"
 	"io"
@@ -18,6 +35,8 @@ import (
 	"os"
 	"sort"
 	"strings"
+
+	"github.com/cv/t/internal/airports"
 )
 
 const (
@@ -25,117 +44,180 @@ const (
 	// (train stations, smaller airports, etc.)
 	// Format: ID, Name, City, Country, IATA, ICAO, Lat, Lon, Alt, TZ Offset, DST, Timezone, Type, Source
 	airportsURL = "https://raw.githubusercontent.com/jpatokal/openflights/master/data/airports-extended.dat"
-	outputFile  = "iata.go"
+	// OpenFlights countries.dat maps the country names used in airports.dat to ISO codes
+	countriesURL = "https://raw.githubusercontent.com/jpatokal/openflights/master/data/countries.dat"
+	// OurAirports airports.csv has names and countries but no timezones
+	ourAirportsURL = "https://davidmegginson.github.io/ourairports-data/airports.csv"
+	outputFile     = "iata.go"
+	airportsFile   = "airports.go"
 )
 
+// parsers maps dataset names accepted by -precedence to their parsers.
+var parsers = map[string]func(io.Reader) ([]airports.Airport, error){
+	airports.SourceOpenFlights: airports.ParseOpenFlights,
+	airports.SourceOurAirports: airports.ParseOurAirports,
+	airports.SourceUNLOCODE:    airports.ParseUNLOCODE,
+}
+
 func main() {
 	log.SetFlags(0)
 	log.SetPrefix("gen: ")
 
-	log.Println("Downloading airport data from OpenFlights...")
-	airports, err := downloadAirports()
-	if err != nil {
-		log.Fatalf("Failed to download airports: %v", err)
+	in := flag.String("in", airportsURL, "OpenFlights airports file or URL (\"-\" for stdin)")
+	countries := flag.String("countries", "", "OpenFlights countries.dat file or URL (default: download when -in is a URL)")
+	ourAirports := flag.String("ourairports", "", "OurAirports airports.csv file or URL (default: download when -in is a URL)")
+	unlocode := flag.String("unlocode", "", "comma-separated UN/LOCODE CSV files or URLs")
+	precedence := flag.String("precedence", "openflights,ourairports,unlocode", "dataset order for names and countries, highest precedence first")
+	conflictsFile := flag.String("conflicts", "", "write the conflict report to this file instead of stderr")
+	flag.Parse()
+
+	if *countries == "" && isURL(*in) {
+		*countries = countriesURL
+	}
+	if *ourAirports == "" && isURL(*in) {
+		*ourAirports = ourAirportsURL
 	}
-	log.Printf("Downloaded %d IATA codes with timezone mappings", len(airports))
 
-	log.Println("Generating iata.go...")
-	if err := generateCode(airports); err != nil {
-		log.Fatalf("Failed to generate code: %v", err)
+	inputs := map[string][]string{
+		airports.SourceOpenFlights: {*in},
+		airports.SourceOurAirports: splitList(*ourAirports),
+		airports.SourceUNLOCODE:    splitList(*unlocode),
 	}
-	log.Println("Done!")
-}
 
-// downloadAirports fetches the OpenFlights CSV and returns a map of IATA code to timezone.
-func downloadAirports() (map[string]string, error) {
-	resp, err := http.Get(airportsURL)
+	order, err := parsePrecedence(*precedence)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP GET failed: %w", err)
+		log.Fatal(err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	var names map[string]string
+	if *countries != "" {
+		log.Printf("Reading countries from %s...", *countries)
+		names, err = load(*countries, airports.ParseOpenFlightsCountries)
+		if err != nil {
+			log.Fatalf("Failed to load countries: %v", err)
+		}
 	}
 
-	return parseOpenFlightsCSV(resp.Body)
-}
+	var datasets [][]airports.Airport
+	for _, name := range order {
+		var records []airports.Airport
+		for _, path := range inputs[name] {
+			log.Printf("Reading %s data from %s...", name, path)
+			part, err := load(path, parsers[name])
+			if err != nil {
+				log.Fatalf("Failed to load %s: %v", name, err)
+			}
+			records = append(records, part...)
+		}
+		if name == airports.SourceOpenFlights {
+			airports.NormalizeCountries(records, names)
+		}
+		datasets = append(datasets, records)
+	}
 
-// parseOpenFlightsCSV parses the OpenFlights airports.dat format.
-// Columns: ID(0), Name(1), City(2), Country(3), IATA(4), ICAO(5), Lat(6), Lon(7),
-// Alt(8), TZ Offset(9), DST(10), Timezone(11), Type(12), Source(13)
-func parseOpenFlightsCSV(r io.Reader) (map[string]string, error) {
-	reader := csv.NewReader(bufio.NewReader(r))
-	reader.FieldsPerRecord = -1 // Allow variable number of fields
+	merged, conflicts := airports.Merge(datasets...)
+	mappings := airports.Zones(merged)
+	log.Printf("Loaded %d IATA codes with timezone mappings", len(mappings))
 
-	airports := make(map[string]string)
+	if err := reportConflicts(*conflictsFile, conflicts); err != nil {
+		log.Fatalf("Failed to write conflict report: %v", err)
+	}
+
+	log.Println("Generating iata.go...")
+	if err := generateCode(mappings); err != nil {
+		log.Fatalf("Failed to generate code: %v", err)
+	}
+	log.Println("Generating airports.go...")
+	if err := generateAirports(merged, mappings); err != nil {
+		log.Fatalf("Failed to generate code: %v", err)
+	}
+	log.Println("Done!")
+}
 
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
+// parsePrecedence validates a -precedence value.
+func parsePrecedence(s string) ([]string, error) {
+	order := splitList(s)
+	seen := make(map[string]bool)
+	for _, name := range order {
+		if _, ok := parsers[name]; !ok {
+			return nil, fmt.Errorf("unknown dataset %q in -precedence", name)
 		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		if seen[name] {
+			return nil, fmt.Errorf("dataset %q listed twice in -precedence", name)
 		}
+		seen[name] = true
+	}
+	if !seen[airports.SourceOpenFlights] {
+		return nil, fmt.Errorf("-precedence must include %s, the only dataset with timezones", airports.SourceOpenFlights)
+	}
+	return order, nil
+}
 
-		// Need at least 12 columns (up to timezone)
-		if len(record) < 12 {
-			continue
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
+	}
+	return items
+}
 
-		iata := strings.TrimSpace(record[4])
-		timezone := strings.TrimSpace(record[11])
+// isURL reports whether a dataset location should be downloaded.
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
 
-		// Skip entries without IATA code or timezone
-		if iata == "" || iata == "\\N" || iata == "-" {
-			continue
-		}
-		if timezone == "" || timezone == "\\N" || timezone == "-" {
-			continue
-		}
+// load parses a dataset from a URL, a file, or stdin ("-").
+func load[T any](path string, parse func(io.Reader) (T, error)) (T, error) {
+	var zero T
 
-		// Validate IATA code format: 3 alphanumeric characters, starting with a letter
-		// (filters out Cyrillic codes, numeric-only codes, etc.)
-		if !isValidIATA(iata) {
-			continue
+	switch {
+	case path == "-":
+		return parse(os.Stdin)
+	case isURL(path):
+		resp, err := http.Get(path)
+		if err != nil {
+			return zero, fmt.Errorf("HTTP GET failed: %w", err)
 		}
+		defer resp.Body.Close()
 
-		// Validate timezone looks like IANA format (contains /)
-		if !strings.Contains(timezone, "/") {
-			continue
+		if resp.StatusCode != http.StatusOK {
+			return zero, fmt.Errorf("unexpected status: %s", resp.Status)
 		}
-
-		// Store the mapping (later entries overwrite earlier ones if duplicates)
-		airports[iata] = timezone
+		return parse(resp.Body)
+	default:
+		f, err := os.Open(path)
+		if err != nil {
+			return zero, err
+		}
+		defer f.Close()
+		return parse(f)
 	}
-
-	return airports, nil
 }
 
-// isValidIATA checks if a string is a valid IATA code.
-// Valid codes are 3 alphanumeric ASCII characters, starting with a letter.
-// NOTE: This duplicates codes.IsValidIATA because gen.go is a standalone program.
-func isValidIATA(s string) bool {
-	if len(s) != 3 {
-		return false
-	}
-	// First character must be a letter
-	if c := s[0]; !((c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')) {
-		return false
-	}
-	// Remaining characters must be alphanumeric
-	for i := 1; i < len(s); i++ {
-		c := s[i]
-		if !((c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')) {
-			return false
+// reportConflicts writes one line per conflict to path, or logs them if path is empty.
+func reportConflicts(path string, conflicts []airports.Conflict) error {
+	log.Printf("Found %d conflicts between datasets", len(conflicts))
+
+	if path == "" {
+		for _, c := range conflicts {
+			log.Println(c)
 		}
+		return nil
 	}
-	return true
+
+	var buf bytes.Buffer
+	for _, c := range conflicts {
+		buf.WriteString(c.String())
+		buf.WriteByte('\n')
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
 }
 
 // generateCode writes the iata.go file with the given mappings.
-func generateCode(airports map[string]string) error {
+func generateCode(mappings map[string]string) error {
 	var buf bytes.Buffer
 
 	buf.WriteString(`// Code generated by go generate; DO NOT EDIT.
@@ -150,14 +232,14 @@ var IATA = map[string]string{
 `)
 
 	// Sort keys for deterministic output
-	keys := make([]string, 0, len(airports))
-	for k := range airports {
+	keys := make([]string, 0, len(mappings))
+	for k := range mappings {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 
 	for _, code := range keys {
-		tz := airports[code]
+		tz := mappings[code]
 		buf.WriteString(fmt.Sprintf("\t%q: %q,\n", code, tz))
 	}
 
@@ -175,3 +257,47 @@ var IATA = map[string]string{
 
 	return nil
 }
+
+// generateAirports writes the airports.go file with the name, city and country
+// of each code in mappings.
+func generateAirports(merged map[string]airports.Airport, mappings map[string]string) error {
+	var buf bytes.Buffer
+
+	buf.WriteString(`// Code generated by go generate; DO NOT EDIT.
+// Source: OpenFlights (https://github.com/jpatokal/openflights)
+
+package codes
+
+// Airports maps codes in IATA to the name, city and country of the place.
+// For example, "SFO" maps to San Francisco International Airport in the US.
+var Airports = map[string]Airport{
+`)
+
+	keys := make([]string, 0, len(mappings))
+	for k := range mappings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, code := range keys {
+		a := merged[code]
+		country := a.Country
+		if len(country) != 2 {
+			country = "" // a country name NormalizeCountries couldn't map
+		}
+		buf.WriteString(fmt.Sprintf("\t%q: {Name: %q, City: %q, Country: %q},\n", code, a.Name, a.City, country))
+	}
+
+	buf.WriteString("}\n")
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format code: %w", err)
+	}
+
+	if err := os.WriteFile(airportsFile, formatted, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
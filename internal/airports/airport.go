@@ -0,0 +1,185 @@
+package airports
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Dataset names, used as Airport.Source and in the generator's -precedence flag.
+const (
+	SourceOpenFlights = "openflights"
+	SourceOurAirports = "ourairports"
+	SourceUNLOCODE    = "unlocode"
+)
+
+// Airport is one dataset's record for an IATA code.
+type Airport struct {
+	IATA string
+	Name string
+	City string
+	// Country is an ISO 3166-1 alpha-2 code, or the dataset's country name if
+	// it couldn't be normalized (see NormalizeCountries)
+	Country string
+	// Timezone is an IANA zone name, or "" if the dataset has none
+	Timezone string
+	// Source is the dataset the record came from
+	Source string
+	// Line is the record's line number in the dataset
+	Line int
+}
+
+// String identifies the record for conflict reports, e.g. "openflights:512".
+func (a Airport) String() string {
+	return fmt.Sprintf("%s:%d", a.Source, a.Line)
+}
+
+// Conflict records datasets disagreeing about a field of an IATA code.
+type Conflict struct {
+	IATA string
+	// Field is "zone" or "country"
+	Field string
+	// Chosen is the record whose value was used
+	Chosen Airport
+	// Others are the records that gave a different value
+	Others []Airport
+	// DroppedZone is the zone left out of the merge because its record
+	// placed the code in a different country than Chosen did
+	DroppedZone string
+}
+
+// String formats the conflict as one line of a conflict report.
+func (c Conflict) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s conflict: using %q from %s", c.IATA, c.Field, c.value(c.Chosen), c.Chosen))
+	for _, other := range c.Others {
+		sb.WriteString(fmt.Sprintf(", not %q from %s", c.value(other), other))
+	}
+	if c.DroppedZone != "" {
+		sb.WriteString(fmt.Sprintf("; dropping zone %q", c.DroppedZone))
+	}
+	return sb.String()
+}
+
+// value returns the conflicting field of a record.
+func (c Conflict) value(a Airport) string {
+	if c.Field == "zone" {
+		return a.Timezone
+	}
+	return a.Country
+}
+
+// Merge combines datasets given in precedence order. For each IATA code and
+// each field, the first dataset that provides a value wins, and within a
+// dataset the first record does. Records that disagree with the chosen zone
+// or country are returned as conflicts, sorted by code.
+//
+// Countries are only compared when both are ISO codes, since datasets that
+// use country names can't be matched against ones that use codes. If the
+// record a code's zone came from gives a different country than the chosen
+// one, the zone is dropped rather than trusted, and the country conflict
+// records it.
+func Merge(datasets ...[]Airport) (map[string]Airport, []Conflict) {
+	merged := make(map[string]Airport)
+	zoneFrom := make(map[string]Airport)
+	countryFrom := make(map[string]Airport)
+	conflicts := make(map[string]*Conflict)
+	var order []string
+
+	addConflict := func(field string, chosen, other Airport) {
+		key := chosen.IATA + "/" + field
+		c, ok := conflicts[key]
+		if !ok {
+			c = &Conflict{IATA: chosen.IATA, Field: field, Chosen: chosen}
+			conflicts[key] = c
+			order = append(order, key)
+		}
+		c.Others = append(c.Others, other)
+	}
+
+	for _, dataset := range datasets {
+		for _, a := range dataset {
+			iata := strings.ToUpper(a.IATA)
+			m, seen := merged[iata]
+			if !seen {
+				m.IATA = iata
+			}
+			if m.Name == "" {
+				m.Name = a.Name
+			}
+			if m.City == "" {
+				m.City = a.City
+			}
+
+			if a.Timezone != "" {
+				if chosen, ok := zoneFrom[iata]; !ok {
+					zoneFrom[iata] = a
+					m.Timezone = a.Timezone
+					m.Source = a.Source
+					m.Line = a.Line
+				} else if chosen.Timezone != a.Timezone {
+					addConflict("zone", chosen, a)
+				}
+			}
+
+			if a.Country != "" {
+				if chosen, ok := countryFrom[iata]; !ok {
+					countryFrom[iata] = a
+					m.Country = a.Country
+				} else if chosen.Country != a.Country && isCountryCode(chosen.Country) && isCountryCode(a.Country) {
+					addConflict("country", chosen, a)
+				}
+			}
+
+			merged[iata] = m
+		}
+	}
+
+	for _, key := range order {
+		c := conflicts[key]
+		from, ok := zoneFrom[c.IATA]
+		if c.Field != "country" || !ok || !isCountryCode(from.Country) || from.Country == c.Chosen.Country {
+			continue
+		}
+		m := merged[c.IATA]
+		c.DroppedZone = m.Timezone
+		m.Timezone = ""
+		merged[c.IATA] = m
+	}
+
+	sort.Strings(order)
+	result := make([]Conflict, 0, len(order))
+	for _, key := range order {
+		result = append(result, *conflicts[key])
+	}
+
+	return merged, result
+}
+
+// Zones returns the IATA code to IANA timezone mapping for the merged
+// airports that have a timezone.
+func Zones(merged map[string]Airport) map[string]string {
+	zones := make(map[string]string, len(merged))
+	for iata, a := range merged {
+		if a.Timezone != "" {
+			zones[iata] = a.Timezone
+		}
+	}
+	return zones
+}
+
+// NormalizeCountries replaces country names with ISO codes using names, a map
+// from country name to ISO 3166-1 alpha-2 code (see ParseOpenFlightsCountries).
+// Names without a mapping are left unchanged.
+func NormalizeCountries(records []Airport, names map[string]string) {
+	for i := range records {
+		if iso, ok := names[records[i].Country]; ok {
+			records[i].Country = iso
+		}
+	}
+}
+
+// isCountryCode reports whether s looks like an ISO 3166-1 alpha-2 code.
+func isCountryCode(s string) bool {
+	return len(s) == 2 && s[0] >= 'A' && s[0] <= 'Z' && s[1] >= 'A' && s[1] <= 'Z'
+}
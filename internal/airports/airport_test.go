@@ -0,0 +1,135 @@
+package airports
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge_FirstRecordWins(t *testing.T) {
+	records, err := ParseOpenFlights(openFixture(t, "duplicates.dat"))
+	require.NoError(t, err)
+
+	merged, conflicts := Merge(records)
+
+	assert.Equal(t, "America/Los_Angeles", merged["SFO"].Timezone, "a later duplicate must not override the first row")
+	assert.Equal(t, "Asia/Dubai", merged["DXB"].Timezone)
+
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "SFO", conflicts[0].IATA)
+	assert.Equal(t, "zone", conflicts[0].Field)
+	assert.Equal(t, 1, conflicts[0].Chosen.Line)
+	require.Len(t, conflicts[0].Others, 1)
+	assert.Equal(t, 3, conflicts[0].Others[0].Line)
+	assert.Equal(t,
+		`SFO: zone conflict: using "America/Los_Angeles" from openflights:1, not "Asia/Manila" from openflights:3`,
+		conflicts[0].String())
+}
+
+func TestMerge_Precedence(t *testing.T) {
+	first := []Airport{{IATA: "ABC", Timezone: "Europe/Paris", Source: "a", Line: 1}}
+	second := []Airport{{IATA: "ABC", Timezone: "Europe/Berlin", Name: "Abc Field", Source: "b", Line: 7}}
+
+	merged, conflicts := Merge(first, second)
+	assert.Equal(t, "Europe/Paris", merged["ABC"].Timezone)
+	assert.Equal(t, "Abc Field", merged["ABC"].Name, "missing fields are filled from later datasets")
+	require.Len(t, conflicts, 1)
+
+	merged, _ = Merge(second, first)
+	assert.Equal(t, "Europe/Berlin", merged["ABC"].Timezone)
+}
+
+func TestMerge_CountryConflicts(t *testing.T) {
+	openflights, err := ParseOpenFlights(openFixture(t, "duplicates.dat"))
+	require.NoError(t, err)
+	countries, err := ParseOpenFlightsCountries(openFixture(t, "countries.dat"))
+	require.NoError(t, err)
+	NormalizeCountries(openflights, countries)
+
+	ourairports, err := ParseOurAirports(openFixture(t, "ourairports.csv"))
+	require.NoError(t, err)
+
+	merged, conflicts := Merge(ourairports, openflights)
+	assert.Equal(t, "US", merged["SFO"].Country)
+	assert.Equal(t, "America/Los_Angeles", merged["SFO"].Timezone, "zones come from the first dataset that has one")
+
+	var fields []string
+	for _, c := range conflicts {
+		fields = append(fields, c.IATA+"/"+c.Field)
+	}
+	assert.Equal(t, []string{"SFO/country", "SFO/zone"}, fields)
+}
+
+func TestMerge_CountryConflictDropsZone(t *testing.T) {
+	ourairports := []Airport{{IATA: "ABC", Country: "FR", Source: SourceOurAirports, Line: 2}}
+	openflights := []Airport{{IATA: "ABC", Country: "DE", Timezone: "Europe/Berlin", Source: SourceOpenFlights, Line: 5}}
+
+	merged, conflicts := Merge(ourairports, openflights)
+	assert.Equal(t, "FR", merged["ABC"].Country)
+	assert.Empty(t, merged["ABC"].Timezone, "a zone from a record in another country isn't trusted")
+	assert.NotContains(t, Zones(merged), "ABC")
+
+	require.Len(t, conflicts, 1)
+	assert.Equal(t,
+		`ABC: country conflict: using "FR" from ourairports:2, not "DE" from openflights:5; dropping zone "Europe/Berlin"`,
+		conflicts[0].String())
+
+	merged, conflicts = Merge(openflights, ourairports)
+	assert.Equal(t, "Europe/Berlin", merged["ABC"].Timezone, "the zone stays when its record's country wins")
+	require.Len(t, conflicts, 1)
+	assert.Empty(t, conflicts[0].DroppedZone)
+}
+
+func TestMerge_CountryNamesNotCompared(t *testing.T) {
+	_, conflicts := Merge(
+		[]Airport{{IATA: "SFO", Country: "US"}},
+		[]Airport{{IATA: "SFO", Country: "United States"}},
+	)
+	assert.Empty(t, conflicts)
+}
+
+func TestParseOpenFlightsCountries(t *testing.T) {
+	got, err := ParseOpenFlightsCountries(openFixture(t, "countries.dat"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"United States":        "US",
+		"United Arab Emirates": "AE",
+		"Philippines":          "PH",
+	}, got)
+}
+
+func TestParseOurAirports(t *testing.T) {
+	got, err := ParseOurAirports(openFixture(t, "ourairports.csv"))
+	require.NoError(t, err)
+
+	require.Len(t, got, 2, "closed airports and rows without an IATA code are skipped")
+	assert.Equal(t, Airport{
+		IATA:    "SFO",
+		Name:    "San Francisco International Airport",
+		City:    "San Francisco",
+		Country: "US",
+		Source:  SourceOurAirports,
+		Line:    2,
+	}, got[0])
+	assert.Equal(t, "DXB", got[1].IATA)
+}
+
+func TestParseOurAirports_MissingColumn(t *testing.T) {
+	_, err := ParseOurAirports(openFixture(t, "airports-extended.dat"))
+	assert.Error(t, err)
+}
+
+func TestParseUNLOCODE(t *testing.T) {
+	got, err := ParseUNLOCODE(openFixture(t, "unlocode.csv"))
+	require.NoError(t, err)
+
+	var codes []string
+	for _, a := range got {
+		codes = append(codes, a.Country+"/"+a.IATA)
+	}
+	assert.Equal(t, []string{"US/SFO", "AE/DXB", "GB/LHR", "GB/LON"}, codes,
+		"only airport locations with valid codes are kept")
+	assert.Equal(t, SourceUNLOCODE, got[0].Source)
+	assert.Empty(t, got[0].Timezone)
+}
@@ -0,0 +1,152 @@
+// Package airports parses open airport datasets for the code generator in
+// codes/gen.go. It must not import the codes package, which is the generator's
+// output and may not compile while it is being regenerated.
+package airports
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseOpenFlights parses the OpenFlights airports.dat format. Every usable row
+// is returned, including duplicate codes, so Merge can report disagreements.
+// Countries are country names; see NormalizeCountries.
+// Columns: ID(0), Name(1), City(2), Country(3), IATA(4), ICAO(5), Lat(6), Lon(7),
+// Alt(8), TZ Offset(9), DST(10), Timezone(11), Type(12), Source(13)
+func ParseOpenFlights(r io.Reader) ([]Airport, error) {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.FieldsPerRecord = -1 // Allow variable number of fields
+
+	var airports []Airport
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		// Need at least 12 columns (up to timezone)
+		if len(record) < 12 {
+			continue
+		}
+
+		iata := strings.TrimSpace(record[4])
+		timezone := strings.TrimSpace(record[11])
+
+		// Skip entries without IATA code or timezone
+		if isNull(iata) || isNull(timezone) {
+			continue
+		}
+
+		// Validate IATA code format: 3 alphanumeric characters, starting with a letter
+		// (filters out Cyrillic codes, numeric-only codes, etc.)
+		if !IsValidIATA(iata) {
+			continue
+		}
+
+		// Validate timezone looks like IANA format (contains /)
+		if !strings.Contains(timezone, "/") {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		airports = append(airports, Airport{
+			IATA:     strings.ToUpper(iata),
+			Name:     nullToEmpty(record[1]),
+			City:     nullToEmpty(record[2]),
+			Country:  nullToEmpty(record[3]),
+			Timezone: timezone,
+			Source:   SourceOpenFlights,
+			Line:     line,
+		})
+	}
+
+	return airports, nil
+}
+
+// ParseOpenFlightsCSV parses the OpenFlights airports.dat format and returns a
+// map of IATA code to IANA timezone. When a code appears more than once, the
+// first row wins; use ParseOpenFlights and Merge to see the disagreements.
+func ParseOpenFlightsCSV(r io.Reader) (map[string]string, error) {
+	records, err := ParseOpenFlights(r)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, _ := Merge(records)
+	return Zones(merged), nil
+}
+
+// ParseOpenFlightsCountries parses the OpenFlights countries.dat format and
+// returns a map of country name to ISO 3166-1 alpha-2 code.
+// Columns: Name(0), ISO code(1), DAFIF code(2)
+func ParseOpenFlightsCountries(r io.Reader) (map[string]string, error) {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.FieldsPerRecord = -1
+
+	countries := make(map[string]string)
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		if len(record) < 2 {
+			continue
+		}
+
+		name := strings.TrimSpace(record[0])
+		iso := strings.TrimSpace(record[1])
+		if isNull(name) || !isCountryCode(iso) {
+			continue
+		}
+		countries[name] = iso
+	}
+
+	return countries, nil
+}
+
+// isNull reports whether an OpenFlights field is empty or one of its null markers.
+func isNull(s string) bool {
+	return s == "" || s == "\\N" || s == "-"
+}
+
+// nullToEmpty trims a field and maps OpenFlights null markers to "".
+func nullToEmpty(s string) string {
+	s = strings.TrimSpace(s)
+	if isNull(s) {
+		return ""
+	}
+	return s
+}
+
+// IsValidIATA checks if a string is a valid IATA code.
+// Valid codes are 3 alphanumeric ASCII characters, starting with a letter.
+// NOTE: This duplicates codes.IsValidIATA; see the package comment.
+func IsValidIATA(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	// First character must be a letter
+	if c := s[0]; !((c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')) {
+		return false
+	}
+	// Remaining characters must be alphanumeric
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if !((c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')) {
+			return false
+		}
+	}
+	return true
+}
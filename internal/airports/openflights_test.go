@@ -0,0 +1,86 @@
+package airports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openFixture opens a file from testdata.
+func openFixture(t *testing.T, name string) *os.File {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	return f
+}
+
+func TestParseOpenFlightsCSV(t *testing.T) {
+	got, err := ParseOpenFlightsCSV(openFixture(t, "airports-extended.dat"))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"GKA": "Pacific/Port_Moresby",
+		"SFO": "America/Los_Angeles",
+		"JFK": "America/New_York",
+		"LHR": "Europe/London",
+		"LON": "Europe/London",
+		"BOM": "Asia/Calcutta",
+	}, got)
+}
+
+func TestParseOpenFlightsCSV_SkipsInvalidRows(t *testing.T) {
+	got, err := ParseOpenFlightsCSV(openFixture(t, "airports-extended.dat"))
+	require.NoError(t, err)
+
+	assert.NotContains(t, got, "NTZ", "rows without a timezone are skipped")
+	assert.NotContains(t, got, "OTZ", "timezones that aren't IANA names are skipped")
+	assert.NotContains(t, got, "0G6", "codes must start with a letter")
+	assert.NotContains(t, got, "ИКУ", "codes must be ASCII")
+	assert.NotContains(t, got, "\\N")
+	assert.NotContains(t, got, "-")
+}
+
+func TestParseOpenFlightsCSV_DuplicateKeepsFirst(t *testing.T) {
+	got, err := ParseOpenFlightsCSV(openFixture(t, "duplicates.dat"))
+	require.NoError(t, err)
+	assert.Equal(t, "America/Los_Angeles", got["SFO"])
+}
+
+func TestParseOpenFlightsCSV_Malformed(t *testing.T) {
+	_, err := ParseOpenFlightsCSV(openFixture(t, "malformed.dat"))
+	assert.Error(t, err)
+}
+
+func TestParseOpenFlightsCSV_Empty(t *testing.T) {
+	got, err := ParseOpenFlightsCSV(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestIsValidIATA(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"SFO", true},
+		{"A1B", true},
+		{"sfo", true},
+		{"", false},
+		{"AB", false},
+		{"ABCD", false},
+		{"1AB", false},
+		{"ИКУ", false},
+		{"A-B", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsValidIATA(tt.code))
+		})
+	}
+}
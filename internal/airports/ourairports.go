@@ -0,0 +1,73 @@
+package airports
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseOurAirports parses the OurAirports airports.csv format. Columns are
+// found by name from the header row. OurAirports has no timezones, but its
+// ISO country codes let Merge catch codes that point at the wrong country.
+// Closed airports are skipped, since their codes are often reused.
+func ParseOurAirports(r io.Reader) ([]Airport, error) {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"iata_code", "name", "iso_country"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column %q in header", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var airports []Airport
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		iata := field(record, "iata_code")
+		if !IsValidIATA(iata) || field(record, "type") == "closed" {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		airports = append(airports, Airport{
+			IATA:    strings.ToUpper(iata),
+			Name:    field(record, "name"),
+			City:    field(record, "municipality"),
+			Country: field(record, "iso_country"),
+			Source:  SourceOurAirports,
+			Line:    line,
+		})
+	}
+
+	return airports, nil
+}
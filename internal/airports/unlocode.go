@@ -0,0 +1,67 @@
+package airports
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// unlocodeAirport is the position of the airport flag in a UN/LOCODE function
+// classifier such as "--34----": 1 port, 2 rail, 3 road, 4 airport.
+const unlocodeAirport = 3
+
+// ParseUNLOCODE parses a UN/LOCODE code list CSV. Only locations with an
+// airport function are returned. Their IATA code is the IATA column, or the
+// location part of the LOCODE when that column is empty, which per the
+// UN/LOCODE manual means the two are the same.
+// Columns: Change(0), Country(1), Location(2), Name(3), NameWoDiacritics(4),
+// Subdivision(5), Function(6), Status(7), Date(8), IATA(9), Coordinates(10), Remarks(11)
+func ParseUNLOCODE(r io.Reader) ([]Airport, error) {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true // Remarks contain stray quotes
+
+	var airports []Airport
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		if len(record) < 10 {
+			continue
+		}
+
+		country := strings.TrimSpace(record[1])
+		location := strings.TrimSpace(record[2])
+		function := strings.TrimSpace(record[6])
+		if location == "" || len(function) <= unlocodeAirport || function[unlocodeAirport] != '4' {
+			continue
+		}
+
+		iata := strings.TrimSpace(record[9])
+		if iata == "" {
+			iata = location
+		}
+		if !IsValidIATA(iata) {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		airports = append(airports, Airport{
+			IATA:    strings.ToUpper(iata),
+			City:    strings.TrimSpace(record[4]),
+			Country: country,
+			Source:  SourceUNLOCODE,
+			Line:    line,
+		})
+	}
+
+	return airports, nil
+}
@@ -8,7 +8,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/cv/t/codes"
+	"github.com/cv/t/internal/tzdata"
 )
 
 const (
@@ -20,8 +20,12 @@ const (
 	LayoutDate = "Mon Jan 2"
 )
 
-// timeSpecRegex matches patterns like "SFO@9:00", "jfk@14:30", "lhr@9"
-var timeSpecRegex = regexp.MustCompile(`(?i)^([A-Z]{3})@(\d{1,2}):?(\d{2})?$`)
+// timeSpecRegex matches patterns like "SFO@9:00", "jfk@14:30", "lhr@9", "ber1@9"
+var timeSpecRegex = regexp.MustCompile(`(?i)^([A-Z][A-Z0-9_-]*)@(\d{1,2}):?(\d{2})?$`)
+
+// iataRegex matches three-letter codes, the only codes ParseTimeSpec accepts
+// without checking that they exist.
+var iataRegex = regexp.MustCompile(`(?i)^[A-Z]{3}$`)
 
 var clocksLow = []string{
 	"🕛", "🕐", "🕑", "🕒", "🕓", "🕔", "🕕", "🕖", "🕗", "🕘", "🕙", "🕚",
@@ -39,13 +43,20 @@ type TimeResult struct {
 	Time     time.Time
 	Location string
 	Found    bool
+	// Err is set when the code is known but its zone data could not be loaded
+	Err error
 }
 
 // RelativeOffset calculates the offset of t's timezone from the local timezone.
 // Returns a string like "(+8h)", "(-5h)", "(+5h30m)", or "(+0h)" if same timezone.
 func RelativeOffset(t time.Time) string {
-	localLoc := time.Local
-	localTime := t.In(localLoc)
+	return RelativeOffsetFrom(t, time.Local)
+}
+
+// RelativeOffsetFrom calculates the offset of t's timezone from ref's at the
+// same instant, in the same format as RelativeOffset.
+func RelativeOffsetFrom(t time.Time, ref *time.Location) string {
+	localTime := t.In(ref)
 
 	// Get the offset in seconds for both timezones at this instant
 	_, targetOffset := t.Zone()
@@ -79,11 +90,25 @@ func RelativeOffset(t time.Time) string {
 	return fmt.Sprintf("(%s%dh%dm)", sign, hours, minutes)
 }
 
-// ClockEmoji returns the appropriate clock emoji for the given time.
-// It uses half-hour emojis when minutes > 30.
+// ClockEmoji returns the clock emoji nearest the given time, to the half hour.
 func ClockEmoji(t time.Time) string {
-	hour := t.Hour()
-	if t.Minute() > 30 {
+	return ClockFace(NearestHalfHour(t))
+}
+
+// NearestHalfHour rounds t to the nearest half hour, returning the hour
+// (0-23) and whether it is half past. Times from a quarter to the hour
+// round up, so 11:50 gives 12 o'clock and 23:50 gives 0 o'clock.
+func NearestHalfHour(t time.Time) (hour int, halfPast bool) {
+	seconds := t.Hour()*3600 + t.Minute()*60 + t.Second()
+	halves := (seconds + 15*60) / (30 * 60)
+	return halves / 2 % 24, halves%2 == 1
+}
+
+// ClockFace returns the clock emoji for an hour (0-23), on the hour or
+// half past. Other hours wrap around the clock, so -1 is 23.
+func ClockFace(hour int, halfPast bool) string {
+	hour = (hour%24 + 24) % 24
+	if halfPast {
 		return clocksHigh[hour]
 	}
 	return clocksLow[hour]
@@ -94,7 +119,7 @@ func ClockEmoji(t time.Time) string {
 func LookupTime(iata string, now *time.Time) TimeResult {
 	iata = strings.ToUpper(iata)
 
-	locName, found := codes.IATA[iata]
+	locName, found := LocationName(iata)
 	if !found {
 		return TimeResult{
 			IATA:  iata,
@@ -102,11 +127,13 @@ func LookupTime(iata string, now *time.Time) TimeResult {
 		}
 	}
 
-	loc, err := time.LoadLocation(locName)
+	loc, err := tzdata.LoadLocation(locName)
 	if err != nil {
 		return TimeResult{
-			IATA:  iata,
-			Found: false,
+			IATA:     iata,
+			Location: locName,
+			Found:    false,
+			Err:      fmt.Errorf("loading location %s: %w", locName, err),
 		}
 	}
 
@@ -125,43 +152,62 @@ func LookupTime(iata string, now *time.Time) TimeResult {
 	}
 }
 
-// FormatResult formats a TimeResult for display.
-// If ps1Format is true, outputs a compact format suitable for shell prompts.
-// If showDate is true, includes the date alongside the time.
-// If showDST is true, includes DST warnings when a transition is near.
-func FormatResult(r TimeResult, ps1Format, showDate bool) string {
-	return FormatResultWithDST(r, ps1Format, showDate, false, DefaultDSTWindow)
-}
-
 // FormatResultWithDST formats a TimeResult for display with optional DST warnings.
 // If ps1Format is true, outputs a compact format suitable for shell prompts.
 // If showDate is true, includes the date alongside the time.
 // If showDST is true, includes DST warnings when a transition is near.
 // dstWindow specifies how many days to look for DST transitions.
 func FormatResultWithDST(r TimeResult, ps1Format, showDate, showDST bool, dstWindow int) string {
+	return FormatResult(r, DisplayOptions{
+		PS1Format: ps1Format,
+		ShowDate:  showDate,
+		ShowDST:   showDST,
+		DSTWindow: dstWindow,
+	})
+}
+
+// FormatResult formats a TimeResult for display. Unlike ShowAll, it shows
+// the date only if opts.ShowDate is set.
+func FormatResult(r TimeResult, opts DisplayOptions) string {
+	if opts.PS1Format && opts.Prompt != nil {
+		return opts.Prompt.Execute(r, opts)
+	}
+
 	if !r.Found {
-		return fmt.Sprintf("%s: ??:??:?? (Unknown)\n", r.IATA)
+		if r.Err != nil {
+			return opts.style(fmt.Sprintf("%s ??:??:?? (%v)\n", opts.label(r.IATA), r.Err), styleUnknown)
+		}
+		return opts.style(fmt.Sprintf("%s ??:??:?? (Unknown)\n", opts.label(r.IATA)), styleUnknown)
 	}
 
-	if ps1Format {
-		return fmt.Sprintf("%s %s", r.IATA, r.Time.Format(LayoutShort))
+	layouts := opts.Layouts.orDefault()
+
+	if opts.PS1Format {
+		return fmt.Sprintf("%s %s", r.IATA, opts.Locale.Format(r.Time, layouts.Short))
 	}
 
-	emoji := ClockEmoji(r.Time)
-	offset := RelativeOffset(r.Time)
+	icon := opts.Icons.prefix(r.Time)
+	from := opts.from()
+	offset := RelativeOffsetFrom(r.Time, from)
 
 	// Check for DST warning if requested
 	var dstWarning string
-	if showDST {
-		if transition := FindDSTTransition(r.Time, dstWindow); transition != nil {
-			dstWarning = " " + FormatDSTWarning(transition)
+	if opts.ShowDST {
+		if transition := FindDSTTransition(r.Time, opts.DSTWindow); transition != nil {
+			dstWarning = " " + opts.style(opts.symbols(FormatDSTWarning(transition)), styleWarning)
 		}
 	}
 
-	if showDate {
-		return fmt.Sprintf("%s: %s %s %s %s (%s)%s\n", r.IATA, emoji, r.Time.Format(LayoutFull), r.Time.Format(LayoutDate), offset, r.Location, dstWarning)
+	var row string
+	if opts.ShowDate {
+		row = fmt.Sprintf("%s %s%s %s %s (%s)", opts.label(r.IATA), icon, opts.Locale.Format(r.Time, layouts.Full), opts.formatDate(r.Time, from, layouts), offset, r.Location)
+	} else {
+		row = fmt.Sprintf("%s %s%s %s (%s)", opts.label(r.IATA), icon, opts.Locale.Format(r.Time, layouts.Full), offset, r.Location)
+	}
+	if opts.ShowStatus {
+		row += fmt.Sprintf(" [%s]", LocationStatus(r.Time, opts.workHours()))
 	}
-	return fmt.Sprintf("%s: %s %s %s (%s)%s\n", r.IATA, emoji, r.Time.Format(LayoutFull), offset, r.Location, dstWarning)
+	return opts.style(row, opts.timeStyle(r.Time)) + dstWarning + "\n"
 }
 
 // Show writes the time for a given IATA code to the provided writer.
@@ -170,16 +216,7 @@ func FormatResultWithDST(r TimeResult, ps1Format, showDate, showDST bool, dstWin
 // If now is nil, the current time is used.
 func Show(w io.Writer, iata string, ps1Format, showDate bool, now *time.Time) {
 	result := LookupTime(iata, now)
-	_, _ = fmt.Fprint(w, FormatResult(result, ps1Format, showDate))
-}
-
-// ShowAll writes the time for multiple IATA codes to the provided writer.
-// If ps1Format is true, outputs a compact format suitable for shell prompts.
-// If showDate is true, includes the date alongside the time.
-// If showDate is false but dates differ across results, date is shown automatically.
-// If now is nil, the current time is used.
-func ShowAll(w io.Writer, iatas []string, ps1Format, showDate bool, now *time.Time) {
-	ShowAllWithDST(w, iatas, ps1Format, showDate, false, DefaultDSTWindow, now)
+	_, _ = fmt.Fprint(w, FormatResult(result, DisplayOptions{PS1Format: ps1Format, ShowDate: showDate}))
 }
 
 // ShowAllWithDST writes the time for multiple IATA codes to the provided writer.
@@ -190,6 +227,118 @@ func ShowAll(w io.Writer, iatas []string, ps1Format, showDate bool, now *time.Ti
 // dstWindow specifies how many days to look for DST transitions.
 // If now is nil, the current time is used.
 func ShowAllWithDST(w io.Writer, iatas []string, ps1Format, showDate, showDST bool, dstWindow int, now *time.Time) {
+	ShowAll(w, iatas, DisplayOptions{
+		PS1Format: ps1Format,
+		ShowDate:  showDate,
+		ShowDST:   showDST,
+		DSTWindow: dstWindow,
+	}, now)
+}
+
+// DisplayOptions controls how ShowAll displays times.
+type DisplayOptions struct {
+	// PS1Format outputs a compact format suitable for shell prompts
+	PS1Format bool
+	// ShowDate includes the date alongside the time. It is enabled
+	// automatically when dates differ across results.
+	ShowDate bool
+	// ShowDST includes DST warnings when a transition is within DSTWindow days
+	ShowDST   bool
+	DSTWindow int
+	// Group collapses codes that share a zone or offset into one line
+	Group GroupMode
+	// Sort orders the lines; groups are sorted as a whole
+	Sort SortOrder
+	// From is the location relative offsets are shown against; nil means time.Local
+	From *time.Location
+	// Layouts format times and dates; zero fields use DefaultLayouts
+	Layouts Layouts
+	// Locale translates weekday and month names; nil means English
+	Locale *Locale
+	// RelativeDays shows dates as labels relative to the From location's
+	// date ("today", "tomorrow") instead of with Layouts.Date. Conversions
+	// label targets relative to the source.
+	RelativeDays bool
+	// Color styles output with ANSI colors: rows outside WorkHours are
+	// grey, night-time rows dimmed, DST warnings yellow and unknown codes
+	// red. It is ignored in PS1Format.
+	Color bool
+	// ShowStatus annotates each row with its LocationStatus
+	ShowStatus bool
+	// WorkHours are the hours shown unstyled and as working; zero means
+	// DefaultWorkHours
+	WorkHours WorkHours
+	// Icons are the icons shown next to times
+	Icons IconSet
+	// CodeWidth pads codes to this many columns so rows line up.
+	// ShowAll sets it to the widest code if it is zero.
+	CodeWidth int
+	// Prompt renders each code in PS1Format; nil means "CODE HH:MM"
+	Prompt *PromptTemplate
+	// PromptSeparator goes between codes in PS1Format; empty means a space
+	PromptSeparator string
+}
+
+// promptSeparator returns PromptSeparator, or a space if it is empty.
+func (o DisplayOptions) promptSeparator() string {
+	if o.PromptSeparator == "" {
+		return " "
+	}
+	return o.PromptSeparator
+}
+
+// label returns "CODE:" padded to CodeWidth.
+func (o DisplayOptions) label(code string) string {
+	return padRight(code+":", o.CodeWidth+1)
+}
+
+// symbols replaces non-ASCII symbols in s if Icons is IconsASCII.
+func (o DisplayOptions) symbols(s string) string {
+	return o.Icons.symbols(s)
+}
+
+// style wraps s in an ANSI style if Color is set, outside PS1Format.
+func (o DisplayOptions) style(s, style string) string {
+	if !o.Color || o.PS1Format {
+		return s
+	}
+	return colorize(s, style)
+}
+
+// timeStyle returns the style for a displayed time.
+func (o DisplayOptions) timeStyle(t time.Time) string {
+	return timeStyle(t, o.workHours())
+}
+
+// workHours returns WorkHours, or DefaultWorkHours if it is zero.
+func (o DisplayOptions) workHours() WorkHours {
+	if o.WorkHours == (WorkHours{}) {
+		return DefaultWorkHours
+	}
+	return o.WorkHours
+}
+
+// formatDate formats t's date for display, relative to ref's date if
+// RelativeDays is set.
+func (o DisplayOptions) formatDate(t time.Time, ref *time.Location, layouts Layouts) string {
+	if o.RelativeDays {
+		return o.Locale.DayLabel(DayOffset(t, ref))
+	}
+	return o.Locale.Format(t, layouts.Date)
+}
+
+// from returns the location relative offsets and days are shown against.
+func (o DisplayOptions) from() *time.Location {
+	if o.From == nil {
+		return time.Local
+	}
+	return o.From
+}
+
+// ShowAll writes the time for multiple IATA codes to the provided writer.
+// If opts.ShowDate is false but dates differ across results, the date is
+// shown anyway. If now is nil, the current time is used.
+func ShowAll(w io.Writer, iatas []string, opts DisplayOptions, now *time.Time) {
 	// Collect all results first
 	results := make([]TimeResult, len(iatas))
 	for i, iata := range iatas {
@@ -197,15 +346,27 @@ func ShowAllWithDST(w io.Writer, iatas []string, ps1Format, showDate, showDST bo
 	}
 
 	// If showDate is not explicitly requested, check if dates differ
-	if !showDate && !ps1Format && len(results) > 1 {
-		showDate = datesDiffer(results)
+	if !opts.ShowDate && !opts.PS1Format && len(results) > 1 {
+		opts.ShowDate = datesDiffer(results)
+	}
+	if opts.RelativeDays && !opts.ShowDate && !opts.PS1Format {
+		opts.ShowDate = daysDifferFrom(results, opts.from())
+	}
+
+	results = GroupResults(results, opts.Group, opts.ShowDST, opts.DSTWindow)
+	SortResults(results, opts.Sort)
+
+	if opts.CodeWidth == 0 && !opts.PS1Format {
+		for _, r := range results {
+			opts.CodeWidth = max(opts.CodeWidth, displayWidth(r.IATA))
+		}
 	}
 
 	// Output results
 	for i, result := range results {
-		_, _ = fmt.Fprint(w, FormatResultWithDST(result, ps1Format, showDate, showDST, dstWindow))
-		if ps1Format && i < len(results)-1 {
-			_, _ = fmt.Fprint(w, " ")
+		_, _ = fmt.Fprint(w, FormatResult(result, opts))
+		if opts.PS1Format && i < len(results)-1 {
+			_, _ = fmt.Fprint(w, opts.promptSeparator())
 		}
 	}
 }
@@ -235,12 +396,16 @@ type TimeSpec struct {
 }
 
 // ParseTimeSpec parses a time specification string like "SFO@9:00" or "jfk@14:30".
+// Codes other than three letters are only accepted if they are user-defined.
 // Returns nil if the string is not a valid time spec (just a plain IATA code).
 func ParseTimeSpec(s string) *TimeSpec {
 	matches := timeSpecRegex.FindStringSubmatch(s)
 	if matches == nil {
 		return nil
 	}
+	if !iataRegex.MatchString(matches[1]) && !isUserLocation(matches[1]) {
+		return nil
+	}
 
 	iata := strings.ToUpper(matches[1])
 	hour := 0
@@ -274,12 +439,12 @@ func ParseTimeSpec(s string) *TimeSpec {
 // ResolveTime creates a time.Time for this TimeSpec based on a reference time.
 // The resulting time will be at the specified hour:minute in the IATA location's timezone.
 func (ts *TimeSpec) ResolveTime(ref time.Time) (time.Time, error) {
-	locName, found := codes.IATA[ts.IATA]
+	locName, found := LocationName(ts.IATA)
 	if !found {
 		return time.Time{}, fmt.Errorf("unknown IATA code: %s", ts.IATA)
 	}
 
-	loc, err := time.LoadLocation(locName)
+	loc, err := tzdata.LoadLocation(locName)
 	if err != nil {
 		return time.Time{}, fmt.Errorf("loading location %s: %w", locName, err)
 	}
@@ -302,23 +467,37 @@ type ConversionResult struct {
 	Targets []TimeResult
 }
 
-// FormatConversion formats a conversion result for display.
-func FormatConversion(c *ConversionResult, ps1Format bool) string {
+// FormatConversion formats a conversion result for display using
+// the PS1Format, Layouts, Locale and RelativeDays options. Target offsets and
+// day labels are relative to the source location.
+func FormatConversion(c *ConversionResult, opts DisplayOptions) string {
 	if !c.Source.Found {
-		return fmt.Sprintf("%s: Unknown airport code\n", c.Source.IATA)
+		return opts.style(fmt.Sprintf("%s: Unknown airport code\n", c.Source.IATA), styleUnknown)
+	}
+
+	layouts := opts.Layouts.orDefault()
+
+	if opts.PS1Format && opts.Prompt != nil {
+		// Offsets and days in the prompt are relative to the source
+		opts.From = c.Source.Time.Location()
+		var parts []string
+		for _, r := range append([]TimeResult{c.Source}, c.Targets...) {
+			parts = append(parts, opts.Prompt.Execute(r, opts))
+		}
+		return strings.Join(parts, opts.promptSeparator())
 	}
 
-	if ps1Format {
+	if opts.PS1Format {
 		var parts []string
-		parts = append(parts, fmt.Sprintf("%s %s", c.Source.IATA, c.Source.Time.Format(LayoutShort)))
+		parts = append(parts, fmt.Sprintf("%s %s", c.Source.IATA, opts.Locale.Format(c.Source.Time, layouts.Short)))
 		for _, t := range c.Targets {
 			if t.Found {
-				parts = append(parts, fmt.Sprintf("%s %s", t.IATA, t.Time.Format(LayoutShort)))
+				parts = append(parts, fmt.Sprintf("%s %s", t.IATA, opts.Locale.Format(t.Time, layouts.Short)))
 			} else {
 				parts = append(parts, fmt.Sprintf("%s ??:??", t.IATA))
 			}
 		}
-		return strings.Join(parts, " ")
+		return strings.Join(parts, opts.promptSeparator())
 	}
 
 	// Check if dates differ to auto-show dates
@@ -328,27 +507,30 @@ func FormatConversion(c *ConversionResult, ps1Format bool) string {
 	var sb strings.Builder
 
 	// Format source
-	emoji := ClockEmoji(c.Source.Time)
-	if showDate {
-		sb.WriteString(fmt.Sprintf("%s: %s %s %s", c.Source.IATA, emoji, c.Source.Time.Format(LayoutShort), c.Source.Time.Format(LayoutDate)))
+	icon := opts.Icons.prefix(c.Source.Time)
+	sourceStyle := opts.timeStyle(c.Source.Time)
+	if showDate && !opts.RelativeDays {
+		sb.WriteString(opts.style(fmt.Sprintf("%s: %s%s %s", c.Source.IATA, icon, opts.Locale.Format(c.Source.Time, layouts.Short), opts.Locale.Format(c.Source.Time, layouts.Date)), sourceStyle))
 	} else {
-		sb.WriteString(fmt.Sprintf("%s: %s %s", c.Source.IATA, emoji, c.Source.Time.Format(LayoutShort)))
+		sb.WriteString(opts.style(fmt.Sprintf("%s: %s%s", c.Source.IATA, icon, opts.Locale.Format(c.Source.Time, layouts.Short)), sourceStyle))
 	}
 
-	sb.WriteString("  →  ")
+	sb.WriteString(opts.symbols("  →  "))
 
 	// Format targets
 	var targetParts []string
 	for _, t := range c.Targets {
 		if t.Found {
-			tEmoji := ClockEmoji(t.Time)
+			tIcon := opts.Icons.prefix(t.Time)
+			offset := RelativeOffsetFrom(t.Time, c.Source.Time.Location())
+			tStyle := opts.timeStyle(t.Time)
 			if showDate {
-				targetParts = append(targetParts, fmt.Sprintf("%s: %s %s %s", t.IATA, tEmoji, t.Time.Format(LayoutShort), t.Time.Format(LayoutDate)))
+				targetParts = append(targetParts, opts.style(fmt.Sprintf("%s: %s%s %s %s", t.IATA, tIcon, opts.Locale.Format(t.Time, layouts.Short), opts.formatDate(t.Time, c.Source.Time.Location(), layouts), offset), tStyle))
 			} else {
-				targetParts = append(targetParts, fmt.Sprintf("%s: %s %s", t.IATA, tEmoji, t.Time.Format(LayoutShort)))
+				targetParts = append(targetParts, opts.style(fmt.Sprintf("%s: %s%s %s", t.IATA, tIcon, opts.Locale.Format(t.Time, layouts.Short), offset), tStyle))
 			}
 		} else {
-			targetParts = append(targetParts, fmt.Sprintf("%s: ??:??", t.IATA))
+			targetParts = append(targetParts, opts.style(fmt.Sprintf("%s: ??:??", t.IATA), styleUnknown))
 		}
 	}
 	sb.WriteString(strings.Join(targetParts, ", "))
@@ -360,7 +542,7 @@ func FormatConversion(c *ConversionResult, ps1Format bool) string {
 // ShowConversion displays a time conversion from a source location to multiple targets.
 // sourceSpec is a time specification like "SFO@9:00".
 // targets are IATA codes to convert to.
-func ShowConversion(w io.Writer, sourceSpec TimeSpec, targets []string, ps1Format bool, now *time.Time) {
+func ShowConversion(w io.Writer, sourceSpec TimeSpec, targets []string, opts DisplayOptions, now *time.Time) {
 	var refTime time.Time
 	if now != nil {
 		refTime = *now
@@ -370,14 +552,19 @@ func ShowConversion(w io.Writer, sourceSpec TimeSpec, targets []string, ps1Forma
 
 	sourceTime, err := sourceSpec.ResolveTime(refTime)
 	if err != nil {
+		if _, known := LocationName(sourceSpec.IATA); known {
+			_, _ = fmt.Fprintf(w, "%s: %v\n", sourceSpec.IATA, err)
+			return
+		}
 		_, _ = fmt.Fprintf(w, "%s: Unknown airport code\n", sourceSpec.IATA)
 		return
 	}
 
+	sourceLoc, _ := LocationName(sourceSpec.IATA)
 	sourceResult := TimeResult{
 		IATA:     sourceSpec.IATA,
 		Time:     sourceTime,
-		Location: codes.IATA[sourceSpec.IATA],
+		Location: sourceLoc,
 		Found:    true,
 	}
 
@@ -391,5 +578,5 @@ func ShowConversion(w io.Writer, sourceSpec TimeSpec, targets []string, ps1Forma
 		Targets: targetResults,
 	}
 
-	_, _ = fmt.Fprint(w, FormatConversion(result, ps1Format))
+	_, _ = fmt.Fprint(w, FormatConversion(result, opts))
 }
@@ -2,6 +2,7 @@ package clock
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -12,39 +13,49 @@ import (
 
 func TestClockEmoji(t *testing.T) {
 	tests := []struct {
-		name    string
-		hour    int
-		minute  int
-		wantLow bool // true if expecting low emoji (minute <= 30)
+		name     string
+		hour     int
+		minute   int
+		second   int
+		wantHour int
+		wantHalf bool
 	}{
-		{name: "midnight low", hour: 0, minute: 0, wantLow: true},
-		{name: "midnight high", hour: 0, minute: 45, wantLow: false},
-		{name: "noon low", hour: 12, minute: 15, wantLow: true},
-		{name: "noon high", hour: 12, minute: 31, wantLow: false},
-		{name: "3pm exactly 30", hour: 15, minute: 30, wantLow: true},
-		{name: "3pm just after 30", hour: 15, minute: 31, wantLow: false},
-		{name: "11pm low", hour: 23, minute: 10, wantLow: true},
-		{name: "11pm high", hour: 23, minute: 55, wantLow: false},
+		{name: "midnight", hour: 0, minute: 0, wantHour: 0},
+		{name: "just before a quarter past", hour: 10, minute: 14, second: 59, wantHour: 10},
+		{name: "a quarter past rounds up", hour: 10, minute: 15, wantHour: 10, wantHalf: true},
+		{name: "10:29 is nearly half past", hour: 10, minute: 29, wantHour: 10, wantHalf: true},
+		{name: "exactly half past", hour: 15, minute: 30, wantHour: 15, wantHalf: true},
+		{name: "10:31 is still half past", hour: 10, minute: 31, wantHour: 10, wantHalf: true},
+		{name: "just before a quarter to", hour: 12, minute: 44, wantHour: 12, wantHalf: true},
+		{name: "a quarter to rounds up", hour: 12, minute: 45, wantHour: 13},
+		{name: "11:50 rolls over to 12", hour: 11, minute: 50, wantHour: 12},
+		{name: "23:50 rolls over to midnight", hour: 23, minute: 50, wantHour: 0},
+		{name: "11pm", hour: 23, minute: 10, wantHour: 23},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			testTime := time.Date(2024, 1, 1, tt.hour, tt.minute, 0, 0, time.UTC)
-			got := ClockEmoji(testTime)
+			testTime := time.Date(2024, 1, 1, tt.hour, tt.minute, tt.second, 0, time.UTC)
 
-			assert.NotEmpty(t, got, "ClockEmoji() should return a non-empty string")
-
-			// Verify it's from the correct set
-			hour := tt.hour % 12
-			if tt.wantLow {
-				assert.Equal(t, clocksLow[hour], got, "should use low emoji set")
-			} else {
-				assert.Equal(t, clocksHigh[hour], got, "should use high emoji set")
-			}
+			hour, half := NearestHalfHour(testTime)
+			assert.Equal(t, tt.wantHour, hour)
+			assert.Equal(t, tt.wantHalf, half)
+			assert.Equal(t, ClockFace(tt.wantHour, tt.wantHalf), ClockEmoji(testTime))
 		})
 	}
 }
 
+func TestClockFace(t *testing.T) {
+	assert.Equal(t, "🕛", ClockFace(0, false))
+	assert.Equal(t, "🕛", ClockFace(12, false))
+	assert.Equal(t, "🕧", ClockFace(12, true))
+	assert.Equal(t, "🕙", ClockFace(22, false))
+	assert.Equal(t, "🕥", ClockFace(10, true))
+	assert.Equal(t, "🕚", ClockFace(-1, false))
+	assert.Equal(t, "🕦", ClockFace(-25, true))
+	assert.Equal(t, "🕐", ClockFace(25, false))
+}
+
 func TestRelativeOffset(t *testing.T) {
 	// Load fixed locations for deterministic tests
 	tokyo, err := time.LoadLocation("Asia/Tokyo")
@@ -256,7 +267,7 @@ func TestFormatResult(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := FormatResult(tt.result, tt.ps1Format, tt.showDate)
+			got := FormatResult(tt.result, DisplayOptions{PS1Format: tt.ps1Format, ShowDate: tt.showDate})
 
 			for _, part := range tt.wantParts {
 				assert.Contains(t, got, part, "output should contain expected part")
@@ -270,6 +281,19 @@ func TestFormatResult(t *testing.T) {
 	}
 }
 
+func TestFormatResultLoadError(t *testing.T) {
+	result := TimeResult{
+		IATA:     "SFO",
+		Location: "America/Los_Angeles",
+		Err:      errors.New("loading location America/Los_Angeles: unknown time zone America/Los_Angeles"),
+	}
+	got := FormatResult(result, DisplayOptions{})
+
+	assert.Contains(t, got, "SFO: ??:??:??")
+	assert.Contains(t, got, "unknown time zone America/Los_Angeles")
+	assert.NotContains(t, got, "(Unknown)")
+}
+
 func TestFormatResultContainsEmoji(t *testing.T) {
 	fixedTime := time.Date(2024, 6, 15, 15, 0, 0, 0, time.UTC)
 	result := TimeResult{
@@ -279,7 +303,7 @@ func TestFormatResultContainsEmoji(t *testing.T) {
 		Found:    true,
 	}
 
-	got := FormatResult(result, false, false)
+	got := FormatResult(result, DisplayOptions{})
 
 	// Should contain a clock emoji
 	hasEmoji := false
@@ -323,7 +347,7 @@ func TestFormatResultContainsOffset(t *testing.T) {
 		Found:    true,
 	}
 
-	got := FormatResult(result, false, false)
+	got := FormatResult(result, DisplayOptions{})
 
 	// Tokyo is UTC+9, London is UTC+0 in winter, so offset should be +9h
 	assert.Contains(t, got, "(+9h)", "output should contain relative offset")
@@ -341,7 +365,7 @@ func TestFormatResultPS1NoOffset(t *testing.T) {
 		Found:    true,
 	}
 
-	got := FormatResult(result, true, false)
+	got := FormatResult(result, DisplayOptions{PS1Format: true})
 
 	assert.NotContains(t, got, "(+", "ps1 format should not contain offset")
 	assert.NotContains(t, got, "(-", "ps1 format should not contain offset")
@@ -450,7 +474,7 @@ func TestShowAll(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var buf bytes.Buffer
-			ShowAll(&buf, tt.iatas, tt.ps1Format, tt.showDate, &fixedTime)
+			ShowAll(&buf, tt.iatas, DisplayOptions{PS1Format: tt.ps1Format, ShowDate: tt.showDate}, &fixedTime)
 			got := buf.String()
 
 			for _, part := range tt.wantParts {
@@ -464,7 +488,7 @@ func TestShowAllPS1Spacing(t *testing.T) {
 	fixedTime := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
 
 	var buf bytes.Buffer
-	ShowAll(&buf, []string{"SFO", "JFK", "LHR"}, true, false, &fixedTime)
+	ShowAll(&buf, []string{"SFO", "JFK", "LHR"}, DisplayOptions{PS1Format: true}, &fixedTime)
 	got := buf.String()
 
 	// Should have spaces between entries: "SFO HH:MM JFK HH:MM LHR HH:MM"
@@ -478,7 +502,7 @@ func TestShowAllAutoDateWhenDatesDiffer(t *testing.T) {
 	fixedTime := time.Date(2024, 6, 15, 23, 0, 0, 0, time.UTC)
 
 	var buf bytes.Buffer
-	ShowAll(&buf, []string{"SFO", "NRT"}, false, false, &fixedTime)
+	ShowAll(&buf, []string{"SFO", "NRT"}, DisplayOptions{}, &fixedTime)
 	got := buf.String()
 
 	// Should auto-show date because dates differ
@@ -491,7 +515,7 @@ func TestShowAllNoAutoDateWhenSameDate(t *testing.T) {
 	fixedTime := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
 
 	var buf bytes.Buffer
-	ShowAll(&buf, []string{"SFO", "JFK"}, false, false, &fixedTime)
+	ShowAll(&buf, []string{"SFO", "JFK"}, DisplayOptions{}, &fixedTime)
 	got := buf.String()
 
 	// Should NOT auto-show date because dates are the same
@@ -723,7 +747,7 @@ func TestShowConversion(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var buf bytes.Buffer
-			ShowConversion(&buf, tt.spec, tt.targets, tt.ps1Format, &refTime)
+			ShowConversion(&buf, tt.spec, tt.targets, DisplayOptions{PS1Format: tt.ps1Format}, &refTime)
 			got := buf.String()
 
 			for _, part := range tt.wantParts {
@@ -738,7 +762,7 @@ func TestShowConversionUnknownSource(t *testing.T) {
 
 	var buf bytes.Buffer
 	spec := TimeSpec{IATA: "XXX", Hour: 9, Minute: 0}
-	ShowConversion(&buf, spec, []string{"JFK"}, false, &refTime)
+	ShowConversion(&buf, spec, []string{"JFK"}, DisplayOptions{}, &refTime)
 	got := buf.String()
 
 	assert.Contains(t, got, "XXX:", "should mention unknown airport")
@@ -752,7 +776,7 @@ func TestShowConversionAutoDate(t *testing.T) {
 
 	var buf bytes.Buffer
 	spec := TimeSpec{IATA: "SFO", Hour: 20, Minute: 0} // 8pm in SFO = 12pm UTC next day = early morning in Tokyo
-	ShowConversion(&buf, spec, []string{"NRT"}, false, &refTime)
+	ShowConversion(&buf, spec, []string{"NRT"}, DisplayOptions{}, &refTime)
 	got := buf.String()
 
 	// At 8pm SFO time (UTC-7 in summer), it's 3am the next day in Tokyo (UTC+9)
@@ -832,7 +856,7 @@ func TestFormatConversion(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := FormatConversion(tt.result, tt.ps1Format)
+			got := FormatConversion(tt.result, DisplayOptions{PS1Format: tt.ps1Format})
 
 			for _, part := range tt.wantParts {
 				assert.Contains(t, got, part, "output should contain %q", part)
@@ -840,3 +864,40 @@ func TestFormatConversion(t *testing.T) {
 		})
 	}
 }
+
+func TestRelativeOffsetFrom(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+	la, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+
+	winter := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	summer := time.Date(2024, 7, 15, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "(+5h30m)", RelativeOffsetFrom(winter.In(kolkata), london))
+	assert.Equal(t, "(+4h30m)", RelativeOffsetFrom(summer.In(kolkata), london))
+	assert.Equal(t, "(-8h)", RelativeOffsetFrom(winter.In(la), london))
+	assert.Equal(t, "(+0h)", RelativeOffsetFrom(winter.In(london), london))
+	assert.Equal(t, RelativeOffset(winter.In(kolkata)), RelativeOffsetFrom(winter.In(kolkata), time.Local))
+}
+
+func TestFormatResult_From(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	got := FormatResult(LookupTime("NRT", &now), DisplayOptions{From: london})
+	assert.Contains(t, got, "21:00:00 (+9h) (Asia/Tokyo)")
+}
+
+func TestFormatConversion_OffsetsFromSource(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowConversion(&buf, TimeSpec{IATA: "SFO", Hour: 9}, []string{"JFK", "BOM", "XXX"}, DisplayOptions{}, &now)
+	output := buf.String()
+	assert.Contains(t, output, "SFO: 🕘 09:00  →  JFK: 🕛 12:00 (+3h), BOM: ")
+	assert.Contains(t, output, " 22:30 (+13h30m), XXX: ??:??\n")
+}
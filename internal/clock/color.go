@@ -0,0 +1,101 @@
+package clock
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ColorMode controls when output is styled with ANSI colors.
+type ColorMode int
+
+const (
+	// ColorAuto colors output written to a terminal unless NO_COLOR is set
+	ColorAuto ColorMode = iota
+	// ColorAlways colors output regardless of where it goes
+	ColorAlways
+	// ColorNever never colors output
+	ColorNever
+)
+
+// ANSI styles for displayed times.
+const (
+	styleReset    = "\x1b[0m"
+	styleOffHours = "\x1b[90m"   // grey: outside working hours
+	styleNight    = "\x1b[2;90m" // dim grey: night time
+	styleWarning  = "\x1b[33m"   // yellow: DST warnings
+	styleUnknown  = "\x1b[31m"   // red: unknown codes
+)
+
+// nightHours are the hours outside work hours shown as sleeping, and dimmed
+// when color is enabled. Start is later than End, so the range wraps past
+// midnight.
+var nightHours = WorkHours{Start: 22, End: 6}
+
+// ParseColorMode parses a --color value. An empty value means always,
+// so a bare --color forces color on.
+func ParseColorMode(s string) (ColorMode, error) {
+	switch strings.ToLower(s) {
+	case "auto":
+		return ColorAuto, nil
+	case "", "always":
+		return ColorAlways, nil
+	case "never":
+		return ColorNever, nil
+	default:
+		return ColorAuto, fmt.Errorf("invalid color mode: %s (use auto, always or never)", s)
+	}
+}
+
+// ColorEnabled reports whether output to f should be colored in mode.
+// In ColorAuto mode, color is used only if f is a terminal, NO_COLOR is
+// unset or empty, and TERM isn't "dumb".
+func ColorEnabled(mode ColorMode, f *os.File) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(f)
+}
+
+// isTerminal reports whether f is a character device, such as a terminal.
+func isTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// timeStyle returns the style for a time: dimmed at night, grey outside
+// work hours or on weekends, and none during work hours.
+func timeStyle(t time.Time, workHours WorkHours) string {
+	switch LocationStatus(t, workHours) {
+	case StatusSleeping:
+		return styleNight
+	case StatusWorking:
+		return ""
+	default:
+		return styleOffHours
+	}
+}
+
+// colorize wraps s in style, keeping any trailing newline outside it.
+// An empty style leaves s unchanged.
+func colorize(s, style string) string {
+	if style == "" {
+		return s
+	}
+	body := strings.TrimSuffix(s, "\n")
+	return style + body + styleReset + s[len(body):]
+}
@@ -0,0 +1,126 @@
+package clock
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseColorMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ColorMode
+		wantErr bool
+	}{
+		{"", ColorAlways, false},
+		{"always", ColorAlways, false},
+		{"AUTO", ColorAuto, false},
+		{"never", ColorNever, false},
+		{"sometimes", ColorAuto, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseColorMode(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	t.Setenv("NO_COLOR", "")
+	assert.True(t, ColorEnabled(ColorAlways, f))
+	assert.False(t, ColorEnabled(ColorNever, f))
+	assert.False(t, ColorEnabled(ColorAuto, f), "files aren't terminals")
+	assert.False(t, ColorEnabled(ColorAuto, nil))
+
+	t.Setenv("NO_COLOR", "1")
+	assert.True(t, ColorEnabled(ColorAlways, f), "--color=always overrides NO_COLOR")
+}
+
+func TestWorkHours_Contains(t *testing.T) {
+	assert.True(t, DefaultWorkHours.Contains(9))
+	assert.True(t, DefaultWorkHours.Contains(16))
+	assert.False(t, DefaultWorkHours.Contains(17))
+	assert.False(t, DefaultWorkHours.Contains(8))
+
+	assert.True(t, nightHours.Contains(23))
+	assert.True(t, nightHours.Contains(0))
+	assert.True(t, nightHours.Contains(5))
+	assert.False(t, nightHours.Contains(6))
+	assert.False(t, nightHours.Contains(21))
+}
+
+func TestTimeStyle(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2024, 1, 15, hour, 0, 0, 0, time.UTC) }
+
+	assert.Equal(t, "", timeStyle(at(10), DefaultWorkHours))
+	assert.Equal(t, styleOffHours, timeStyle(at(19), DefaultWorkHours))
+	assert.Equal(t, styleNight, timeStyle(at(3), DefaultWorkHours))
+	assert.Equal(t, "", timeStyle(at(19), WorkHours{Start: 12, End: 20}))
+}
+
+func TestColorize(t *testing.T) {
+	assert.Equal(t, "\x1b[31mXYZ\x1b[0m\n", colorize("XYZ\n", styleUnknown))
+	assert.Equal(t, "\x1b[31mXYZ\x1b[0m", colorize("XYZ", styleUnknown))
+	assert.Equal(t, "XYZ\n", colorize("XYZ\n", ""))
+}
+
+func TestFormatResult_Color(t *testing.T) {
+	// Tuesday 05:00 UTC: Monday 21:00 in SFO, 14:00 in Tokyo, 05:00 in London
+	now := time.Date(2024, 1, 16, 5, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowAll(&buf, []string{"SFO", "NRT", "LHR", "XYZ"}, DisplayOptions{Color: true}, &now)
+	lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 4)
+	assert.Regexp(t, `^\x1b\[90mSFO: .*\x1b\[0m$`, string(lines[0]))
+	assert.Regexp(t, `^NRT: [^\x1b]*$`, string(lines[1]))
+	assert.Regexp(t, `^\x1b\[2;90mLHR: .*\x1b\[0m$`, string(lines[2]))
+	assert.Equal(t, "\x1b[31mXYZ: ??:??:?? (Unknown)\x1b[0m", string(lines[3]))
+
+	buf.Reset()
+	ShowAll(&buf, []string{"SFO"}, DisplayOptions{Color: true, WorkHours: WorkHours{Start: 12, End: 22}}, &now)
+	assert.NotContains(t, buf.String(), "\x1b")
+
+	// Weekends are grey during work hours too
+	saturday := time.Date(2024, 1, 13, 12, 0, 0, 0, time.UTC)
+	buf.Reset()
+	ShowAll(&buf, []string{"LHR"}, DisplayOptions{Color: true}, &saturday)
+	assert.Regexp(t, `^\x1b\[90mLHR: `, buf.String())
+
+	buf.Reset()
+	ShowAll(&buf, []string{"SFO", "XYZ"}, DisplayOptions{Color: true, PS1Format: true}, &now)
+	assert.NotContains(t, buf.String(), "\x1b")
+}
+
+func TestFormatResult_ColorDSTWarning(t *testing.T) {
+	// Monday before US DST starts on 2024-03-10, at 10:00 in SFO
+	now := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowAll(&buf, []string{"SFO"}, DisplayOptions{Color: true, ShowDST: true, DSTWindow: 10}, &now)
+	assert.Regexp(t, `^SFO: .*\(America/Los_Angeles\) \x1b\[33m.*DST starts in 6 days.*\x1b\[0m\n$`, buf.String())
+}
+
+func TestFormatConversion_Color(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowConversion(&buf, TimeSpec{IATA: "SFO", Hour: 10}, []string{"NRT", "XYZ"}, DisplayOptions{Color: true}, &now)
+	assert.Regexp(t, `^SFO: \S+ 10:00 .*  →  \x1b\[2;90mNRT: .*\x1b\[0m, \x1b\[31mXYZ: \?\?:\?\?\x1b\[0m\n$`, buf.String())
+}
@@ -0,0 +1,48 @@
+package clock
+
+import (
+	"fmt"
+	"time"
+)
+
+// DayOffset returns how many calendar days t's date is ahead of the date at
+// the same instant in ref: 1 if it is already tomorrow there, -1 if still
+// yesterday.
+func DayOffset(t time.Time, ref *time.Location) int {
+	y, m, d := t.Date()
+	ry, rm, rd := t.In(ref).Date()
+	date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	refDate := time.Date(ry, rm, rd, 0, 0, 0, 0, time.UTC)
+	return int(date.Sub(refDate).Hours() / 24)
+}
+
+// DayLabel names a day offset from DayOffset: "today", "tomorrow",
+// "yesterday" or "+2 days", in the locale's language. A nil Locale labels
+// in English.
+func (l *Locale) DayLabel(offset int) string {
+	if l == nil {
+		l = locales["en"]
+	}
+
+	switch offset {
+	case 0:
+		return l.Today
+	case 1:
+		return l.Tomorrow
+	case -1:
+		return l.Yesterday
+	default:
+		return fmt.Sprintf(l.DayCount, offset)
+	}
+}
+
+// daysDifferFrom returns true if any of the found results fall on a
+// different date than ref does at the same instant.
+func daysDifferFrom(results []TimeResult, ref *time.Location) bool {
+	for _, r := range results {
+		if r.Found && DayOffset(r.Time, ref) != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package clock
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDayOffset(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	la, _ := time.LoadLocation("America/Los_Angeles")
+	kiritimati, _ := time.LoadLocation("Pacific/Kiritimati")
+	pagoPago, _ := time.LoadLocation("Pacific/Pago_Pago")
+
+	// 2024-01-15 05:00 UTC: 21:00 on the 14th in LA, 14:00 on the 15th in Tokyo
+	instant := time.Date(2024, 1, 15, 5, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 1, DayOffset(instant.In(tokyo), la))
+	assert.Equal(t, -1, DayOffset(instant.In(la), tokyo))
+	assert.Equal(t, 0, DayOffset(instant.In(tokyo), tokyo))
+	// UTC+14 and UTC-11 are two calendar days apart from 10:00 to 11:00 UTC
+	instant = time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	assert.Equal(t, 2, DayOffset(instant.In(kiritimati), pagoPago))
+	assert.Equal(t, -2, DayOffset(instant.In(pagoPago), kiritimati))
+	// Across a month boundary
+	instant = time.Date(2024, 2, 29, 20, 0, 0, 0, time.UTC)
+	assert.Equal(t, 1, DayOffset(instant.In(tokyo), la))
+}
+
+func TestLocale_DayLabel(t *testing.T) {
+	var en *Locale
+	assert.Equal(t, "today", en.DayLabel(0))
+	assert.Equal(t, "tomorrow", en.DayLabel(1))
+	assert.Equal(t, "yesterday", en.DayLabel(-1))
+	assert.Equal(t, "+2 days", en.DayLabel(2))
+	assert.Equal(t, "-2 days", en.DayLabel(-2))
+
+	assert.Equal(t, "明日", locales["ja"].DayLabel(1))
+	assert.Equal(t, "ontem", locales["pt"].DayLabel(-1))
+	assert.Equal(t, "+2 Tage", locales["de"].DayLabel(2))
+}
+
+func TestShowAll_RelativeDays(t *testing.T) {
+	la, _ := time.LoadLocation("America/Los_Angeles")
+	now := time.Date(2024, 1, 15, 5, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowAll(&buf, []string{"SFO", "NRT"}, DisplayOptions{RelativeDays: true, From: la}, &now)
+	assert.Contains(t, buf.String(), "SFO: 🕘 21:00:00 today ")
+	assert.Contains(t, buf.String(), "NRT: 🕑 14:00:00 tomorrow ")
+
+	// Labels show whenever a date differs from the reference's, even if
+	// all results share a date
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	buf.Reset()
+	ShowAll(&buf, []string{"SFO"}, DisplayOptions{RelativeDays: true, From: tokyo}, &now)
+	assert.Contains(t, buf.String(), "21:00:00 yesterday ")
+
+	buf.Reset()
+	ShowAll(&buf, []string{"NRT"}, DisplayOptions{RelativeDays: true, From: tokyo}, &now)
+	assert.NotContains(t, buf.String(), "today")
+}
+
+func TestFormatConversion_RelativeDays(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowConversion(&buf, TimeSpec{IATA: "SFO", Hour: 20}, []string{"JFK", "NRT"}, DisplayOptions{RelativeDays: true}, &now)
+	assert.Contains(t, buf.String(), "SFO: 🕗 20:00  →  JFK: 🕚 23:00 today (+3h), NRT: 🕐 13:00 tomorrow (+17h)")
+
+	buf.Reset()
+	ShowConversion(&buf, TimeSpec{IATA: "SFO", Hour: 9}, []string{"JFK"}, DisplayOptions{RelativeDays: true}, &now)
+	assert.NotContains(t, buf.String(), "today")
+}
@@ -0,0 +1,130 @@
+package clock
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cv/t/internal/tzdata"
+)
+
+// CodeFailure records an IATA code whose zone could not be loaded.
+type CodeFailure struct {
+	IATA     string
+	Location string
+	Err      error
+}
+
+// Diagnosis reports which zone data is in use and how well the known codes fit it.
+type Diagnosis struct {
+	TZData tzdata.Info
+	// Codes is the number of known codes, from codes.IATA and user-defined locations
+	Codes int
+	// UserCodes is the number of user-defined codes (see SetUserLocations)
+	UserCodes int
+	// Failures are the codes whose zone failed to load, sorted by code
+	Failures []CodeFailure
+	// Deprecated maps each deprecated zone name in use to the codes using it
+	Deprecated map[string][]string
+}
+
+// Healthy reports whether every code's zone loaded.
+func (d *Diagnosis) Healthy() bool {
+	return len(d.Failures) == 0
+}
+
+// Diagnose loads the zone for every known code, including user-defined ones,
+// and flags deprecated zone names.
+func Diagnose() *Diagnosis {
+	locations := allLocations()
+	d := &Diagnosis{
+		TZData:     tzdata.Current(),
+		Codes:      len(locations),
+		UserCodes:  len(userLocations),
+		Deprecated: make(map[string][]string),
+	}
+
+	iatas := make([]string, 0, len(locations))
+	for iata := range locations {
+		iatas = append(iatas, iata)
+	}
+	sort.Strings(iatas)
+
+	for _, iata := range iatas {
+		locName := locations[iata]
+		if _, err := tzdata.LoadLocation(locName); err != nil {
+			d.Failures = append(d.Failures, CodeFailure{IATA: iata, Location: locName, Err: err})
+		}
+		if _, deprecated := tzdata.Deprecated(locName); deprecated {
+			d.Deprecated[locName] = append(d.Deprecated[locName], iata)
+		}
+	}
+
+	return d
+}
+
+// FormatDiagnosis formats a Diagnosis for display.
+func FormatDiagnosis(d *Diagnosis) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("tzdata source: %s", d.TZData.Source))
+	if d.TZData.Path != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", d.TZData.Path))
+	}
+	sb.WriteString("\n")
+
+	version := d.TZData.Version
+	if version == "" {
+		version = "unknown"
+	}
+	sb.WriteString(fmt.Sprintf("tzdata version: %s\n", version))
+
+	if tzdata.Embedded {
+		sb.WriteString("embedded tzdata: yes\n")
+	} else {
+		sb.WriteString("embedded tzdata: no (build with -tags timetzdata to embed)\n")
+	}
+
+	if d.UserCodes > 0 {
+		sb.WriteString(fmt.Sprintf("user-defined codes: %d (locations.json)\n", d.UserCodes))
+	}
+
+	if d.Healthy() {
+		sb.WriteString(fmt.Sprintf("IATA codes: %d, all zones load\n", d.Codes))
+	} else {
+		sb.WriteString(fmt.Sprintf("IATA codes: %d, %d fail to load:\n", d.Codes, len(d.Failures)))
+		for _, f := range d.Failures {
+			sb.WriteString(fmt.Sprintf("  %s: %s: %v\n", f.IATA, f.Location, f.Err))
+		}
+	}
+
+	if len(d.Deprecated) == 0 {
+		sb.WriteString("Deprecated zone names: none\n")
+		return sb.String()
+	}
+
+	names := make([]string, 0, len(d.Deprecated))
+	total := 0
+	for name, iatas := range d.Deprecated {
+		names = append(names, name)
+		total += len(iatas)
+	}
+	sort.Strings(names)
+
+	sb.WriteString(fmt.Sprintf("Deprecated zone names: %d used by %d codes\n", len(names), total))
+	for _, name := range names {
+		current, _ := tzdata.Deprecated(name)
+		sb.WriteString(fmt.Sprintf("  %s → %s: %s\n", name, current, strings.Join(d.Deprecated[name], " ")))
+	}
+
+	return sb.String()
+}
+
+// ShowDoctor writes a diagnosis of the zone data and IATA codes. Returns
+// false if any code's zone failed to load.
+func ShowDoctor(w io.Writer, icons IconSet) bool {
+	d := Diagnose()
+	_, _ = fmt.Fprint(w, icons.symbols(FormatDiagnosis(d)))
+	return d.Healthy()
+}
@@ -0,0 +1,70 @@
+package clock
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cv/t/codes"
+	"github.com/cv/t/internal/tzdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnose(t *testing.T) {
+	d := Diagnose()
+
+	assert.Equal(t, len(codes.IATA), d.Codes)
+	assert.True(t, d.Healthy(), "every zone should load from the system tz database: %v", d.Failures)
+	assert.Contains(t, d.Deprecated["Asia/Calcutta"], "BOM")
+}
+
+func TestDiagnose_MissingZones(t *testing.T) {
+	// An empty zoneinfo directory makes every code fail
+	require.NoError(t, tzdata.Use(t.TempDir()))
+	t.Cleanup(func() { _ = tzdata.Use("system") })
+
+	d := Diagnose()
+	assert.False(t, d.Healthy())
+	assert.Len(t, d.Failures, len(codes.IATA))
+	assert.Equal(t, tzdata.SourceSelected, d.TZData.Source)
+}
+
+func TestFormatDiagnosis(t *testing.T) {
+	d := &Diagnosis{
+		TZData: tzdata.Info{Source: tzdata.SourceSystem, Path: "/usr/share/zoneinfo", Version: "2024a"},
+		Codes:  3,
+		Failures: []CodeFailure{
+			{IATA: "XXX", Location: "Nowhere/Town", Err: assert.AnError},
+		},
+		Deprecated: map[string][]string{
+			"Asia/Calcutta": {"BOM", "DEL"},
+		},
+	}
+
+	got := FormatDiagnosis(d)
+	assert.Contains(t, got, "tzdata source: system zoneinfo (/usr/share/zoneinfo)")
+	assert.Contains(t, got, "tzdata version: 2024a")
+	assert.Contains(t, got, "IATA codes: 3, 1 fail to load:")
+	assert.Contains(t, got, "XXX: Nowhere/Town:")
+	assert.Contains(t, got, "Deprecated zone names: 1 used by 2 codes")
+	assert.Contains(t, got, "Asia/Calcutta → Asia/Kolkata: BOM DEL")
+
+	d = &Diagnosis{TZData: tzdata.Info{Source: tzdata.SourceEmbedded}, Codes: 3}
+	got = FormatDiagnosis(d)
+	assert.Contains(t, got, "tzdata source: embedded\n")
+	assert.Contains(t, got, "tzdata version: unknown")
+	assert.Contains(t, got, "IATA codes: 3, all zones load")
+	assert.Contains(t, got, "Deprecated zone names: none")
+}
+
+func TestShowDoctor(t *testing.T) {
+	var buf bytes.Buffer
+	assert.True(t, ShowDoctor(&buf, IconsClock))
+	assert.Contains(t, buf.String(), "tzdata source:")
+	require.Contains(t, buf.String(), "Asia/Calcutta → Asia/Kolkata")
+
+	buf.Reset()
+	ShowDoctor(&buf, IconsASCII)
+	assert.Contains(t, buf.String(), "Asia/Calcutta -> Asia/Kolkata")
+	assertASCII(t, buf.String())
+}
@@ -2,6 +2,8 @@ package clock
 
 import (
 	"fmt"
+	"io"
+	"strings"
 	"time"
 )
 
@@ -15,84 +17,170 @@ type DSTTransition struct {
 	OffsetChange string
 	// Description is a human-readable description (e.g., "DST starts", "DST ends")
 	Description string
+	// Kind classifies the transition as seasonal or permanent
+	Kind TransitionKind
+	// Before is the zone in effect immediately before the transition
+	Before ZoneState
+	// After is the zone in effect from the transition onwards
+	After ZoneState
 }
 
+// ZoneState describes the zone in effect for a location at a given instant.
+type ZoneState struct {
+	// Name is the zone abbreviation (e.g., "PST", "BST", "+0530")
+	Name string
+	// Offset is the offset east of UTC in seconds
+	Offset int
+	// DST reports whether the tz database marks the zone as daylight saving time
+	DST bool
+}
+
+// TransitionKind classifies a zone transition.
+type TransitionKind int
+
+const (
+	// DSTStart is a seasonal change that moves the clocks forward.
+	DSTStart TransitionKind = iota
+	// DSTEnd is a seasonal change that moves the clocks back.
+	DSTEnd
+	// PermanentOffsetChange is a permanent change of offset, such as Samoa
+	// crossing the date line in 2011 or a country moving to a different zone.
+	PermanentOffsetChange
+	// RuleChange keeps the offset but changes the abbreviation or DST status,
+	// such as Turkey staying on summer time permanently in 2016.
+	RuleChange
+)
+
+// Permanent reports whether the transition is a one-off rule change rather
+// than part of the yearly DST cycle.
+func (k TransitionKind) Permanent() bool {
+	return k == PermanentOffsetChange || k == RuleChange
+}
+
+// transitionScanStep is how far apart zone offsets are sampled when searching
+// for transitions. A day whose ends disagree is bisected until every change in
+// it is found, but a change that is undone within the same day is invisible.
+// Transitions in the tz database are at least days apart, so none are missed.
+const transitionScanStep = 24 * time.Hour
+
+// layoutTransition shows a wall clock time with its abbreviation and UTC offset.
+const layoutTransition = "15:04:05 MST -07:00"
+
 // DefaultDSTWindow is the default number of days to look ahead/behind for DST changes.
 const DefaultDSTWindow = 5
 
 // FindDSTTransition checks if there's a DST transition within the given window of days
 // for the specified time's location. Returns nil if no transition is found.
+// If the window holds more than one transition, the next one after t is
+// returned, or the most recent one if none is ahead. RuleChange transitions
+// don't move the clocks, so they are skipped.
 func FindDSTTransition(t time.Time, windowDays int) *DSTTransition {
 	loc := t.Location()
 	if loc == time.UTC {
 		return nil // UTC has no DST
 	}
 
-	// Check each day in the window (both past and future)
-	startDay := t.AddDate(0, 0, -windowDays)
-
-	for day := 0; day <= windowDays*2; day++ {
-		checkDay := startDay.AddDate(0, 0, day)
-
-		// Check at the start of each hour on this day for a transition
-		for hour := 0; hour < 24; hour++ {
-			checkTime := time.Date(checkDay.Year(), checkDay.Month(), checkDay.Day(), hour, 0, 0, 0, loc)
-
-			// Skip if this time is too far from our reference time
-			hoursDiff := checkTime.Sub(t).Hours()
-			if hoursDiff < float64(-windowDays*24) || hoursDiff > float64(windowDays*24) {
-				continue
-			}
-
-			// Check if there's a transition at this hour by creating the previous hour explicitly.
-			// We must use time.Date rather than checkTime.Add(-time.Hour) because Add()
-			// preserves the same zone offset, hiding the transition.
-			var prevHour time.Time
-			if hour == 0 {
-				prevDay := checkDay.AddDate(0, 0, -1)
-				prevHour = time.Date(prevDay.Year(), prevDay.Month(), prevDay.Day(), 23, 0, 0, 0, loc)
-			} else {
-				prevHour = time.Date(checkDay.Year(), checkDay.Month(), checkDay.Day(), hour-1, 0, 0, 0, loc)
-			}
-
-			_, prevOffset := prevHour.Zone()
-			_, checkOffset := checkTime.Zone()
-
-			if prevOffset != checkOffset {
-				// Found a transition!
-				offsetDiff := checkOffset - prevOffset
-
-				// Calculate days until transition
-				var daysUntil int
-				if checkTime.After(t) {
-					hoursRemaining := checkTime.Sub(t).Hours()
-					daysUntil = int(hoursRemaining / 24)
-					if hoursRemaining > 0 && int(hoursRemaining)%24 > 0 {
-						daysUntil++ // Round up for future transitions
-					}
-				} else {
-					hoursAgo := t.Sub(checkTime).Hours()
-					daysUntil = -int(hoursAgo / 24)
-					if hoursAgo > 0 && int(hoursAgo)%24 > 0 {
-						daysUntil-- // Round down (more negative) for past transitions
-					}
-				}
-
-				// Only return if we're currently on the "currentOffset" side of the transition
-				// and the transition is in the future, OR we just passed it
-				if daysUntil >= -windowDays && daysUntil <= windowDays {
-					return &DSTTransition{
-						Date:         checkTime,
-						DaysUntil:    daysUntil,
-						OffsetChange: formatOffsetChange(offsetDiff),
-						Description:  dstDescription(offsetDiff),
-					}
-				}
-			}
+	window := time.Duration(windowDays) * 24 * time.Hour
+	var found *DSTTransition
+	for _, tr := range transitionsBetween(loc, t.Add(-window), t.Add(window)) {
+		if tr.Kind == RuleChange {
+			continue
+		}
+		found = &tr
+		if tr.Date.After(t) {
+			break
+		}
+	}
+	if found == nil {
+		return nil
+	}
+	found.DaysUntil = daysBetween(t, found.Date)
+
+	return found
+}
+
+// daysBetween returns the number of days from t until at, rounded away from zero
+// on whole hours: a transition 30 hours away is "in 2 days", one 30 minutes away is "today".
+func daysBetween(t, at time.Time) int {
+	hours := int(at.Sub(t).Hours())
+	days := hours / 24
+	switch {
+	case hours > 0 && hours%24 > 0:
+		days++
+	case hours < 0 && hours%24 < 0:
+		days--
+	}
+	return days
+}
+
+// ListDSTTransitions returns every offset or abbreviation change in loc during
+// the given calendar year, in chronological order. The Date of each transition
+// is the exact instant of the change; DaysUntil is not set.
+func ListDSTTransitions(loc *time.Location, year int) []DSTTransition {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := time.Date(year+1, time.January, 1, 0, 0, 0, 0, loc)
+	return transitionsBetween(loc, start, end)
+}
+
+// transitionsBetween returns the zone transitions in loc within (start, end].
+// It samples the zone every transitionScanStep and bisects any interval whose
+// endpoints disagree, so the cost grows with the number of days, not hours.
+func transitionsBetween(loc *time.Location, start, end time.Time) []DSTTransition {
+	var transitions []DSTTransition
+
+	lo := start.In(loc)
+	loState := zoneState(lo)
+	for lo.Before(end) {
+		hi := lo.Add(transitionScanStep)
+		if hi.After(end) {
+			hi = end.In(loc)
 		}
+
+		hiState := zoneState(hi)
+		// Bisect again from each change until the interval's end is reached,
+		// in case it holds more than one
+		for from := lo; loState != hiState; {
+			at := bisectTransition(from, hi)
+			after := zoneState(at)
+			reversed := loState.DST == after.DST && loState.Offset != after.Offset && offsetReversed(at, loState, after)
+			kind := classifyTransition(loState, after, reversed)
+			transitions = append(transitions, DSTTransition{
+				Date:         at,
+				OffsetChange: formatOffsetChange(after.Offset - loState.Offset),
+				Description:  dstDescription(kind),
+				Kind:         kind,
+				Before:       loState,
+				After:        after,
+			})
+			from, loState = at, after
+		}
+
+		lo = hi
 	}
 
-	return nil
+	return transitions
+}
+
+// bisectTransition returns the instant in (lo, hi] at which the zone changes.
+// lo and hi must be in the same location and fall in different zones.
+// Transitions in the tz database are whole seconds, so bisection stops there.
+func bisectTransition(lo, hi time.Time) time.Time {
+	loState := zoneState(lo)
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if zoneState(mid) == loState {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi.Truncate(time.Second)
+}
+
+// zoneState returns the zone in effect at t in t's location.
+func zoneState(t time.Time) ZoneState {
+	name, offset := t.Zone()
+	return ZoneState{Name: name, Offset: offset, DST: t.IsDST()}
 }
 
 // formatOffsetChange formats an offset difference in seconds as a human-readable string.
@@ -116,12 +204,55 @@ func formatOffsetChange(diffSeconds int) string {
 	return fmt.Sprintf("%s%dh%dm", sign, hours, minutes)
 }
 
+// seasonalWindow is how soon a change that keeps the DST flag must be undone
+// to count as seasonal.
+const seasonalWindow = 366 * 24 * time.Hour
+
+// classifyTransition decides whether a transition is part of the DST cycle.
+// A seasonal change flips the tz database's DST flag and moves the clocks;
+// the direction of the move says whether summer time starts or ends. Going
+// by the offset rather than the flag keeps zones with negative DST, like
+// Europe/Dublin (whose "standard" time is summer time), reading naturally.
+// A change that keeps the flag is permanent unless it is reversed within a
+// year, like Britain's double summer time during the war.
+func classifyTransition(before, after ZoneState, reversed bool) TransitionKind {
+	switch {
+	case before.Offset == after.Offset:
+		return RuleChange
+	case before.DST == after.DST && !reversed:
+		return PermanentOffsetChange
+	case after.Offset > before.Offset:
+		return DSTStart
+	default:
+		return DSTEnd
+	}
+}
+
+// offsetReversed reports whether the change from before to after at the
+// instant at is undone within seasonalWindow: the zone goes back to before's
+// offset afterwards, or was on after's offset shortly before. The zone is
+// sampled every transitionScanStep.
+func offsetReversed(at time.Time, before, after ZoneState) bool {
+	for d := transitionScanStep; d <= seasonalWindow; d += transitionScanStep {
+		if zoneState(at.Add(d)).Offset == before.Offset || zoneState(at.Add(-d)).Offset == after.Offset {
+			return true
+		}
+	}
+	return false
+}
+
 // dstDescription returns a description of the DST transition.
-func dstDescription(offsetDiff int) string {
-	if offsetDiff > 0 {
-		return "DST starts" // Clocks spring forward
+func dstDescription(kind TransitionKind) string {
+	switch kind {
+	case DSTStart:
+		return "DST starts" // Clocks go forward
+	case DSTEnd:
+		return "DST ends" // Clocks go back
+	case PermanentOffsetChange:
+		return "Permanent offset change"
+	default:
+		return "Zone rule change"
 	}
-	return "DST ends" // Clocks fall back
 }
 
 // FormatDSTWarning formats a DST transition as a warning string.
@@ -145,5 +276,64 @@ func FormatDSTWarning(transition *DSTTransition) string {
 		daysStr = fmt.Sprintf("%d days ago", -transition.DaysUntil)
 	}
 
+	switch transition.Kind {
+	case PermanentOffsetChange:
+		return fmt.Sprintf("⚠️ %s %s (%s, %s → %s)", transition.Description, daysStr,
+			transition.OffsetChange, transition.Before.Name, transition.After.Name)
+	case RuleChange:
+		return fmt.Sprintf("⚠️ %s %s (%s → %s)", transition.Description, daysStr,
+			transition.Before.Name, transition.After.Name)
+	}
+
 	return fmt.Sprintf("⚠️ %s %s (%s)", transition.Description, daysStr, transition.OffsetChange)
 }
+
+// FormatDSTList formats the transitions for a location in a year for display.
+// Each transition shows the local wall clock just before and at the change.
+func FormatDSTList(iata, locName string, year int, transitions []DSTTransition) string {
+	var sb strings.Builder
+
+	switch len(transitions) {
+	case 0:
+		sb.WriteString(fmt.Sprintf("%s: no transitions in %d (%s)\n", iata, year, locName))
+		return sb.String()
+	case 1:
+		sb.WriteString(fmt.Sprintf("%s: 1 transition in %d (%s)\n", iata, year, locName))
+	default:
+		sb.WriteString(fmt.Sprintf("%s: %d transitions in %d (%s)\n", iata, len(transitions), year, locName))
+	}
+
+	for _, tr := range transitions {
+		before := tr.Date.In(time.FixedZone(tr.Before.Name, tr.Before.Offset))
+		after := tr.Date.Format(layoutTransition)
+		// Changes like Samoa's in 2011 cross a date line; show the new date too
+		if tr.Date.Format("2006-01-02") != before.Format("2006-01-02") {
+			after = tr.Date.Format(LayoutDate) + " " + after
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s → %s  %s (%s)\n",
+			before.Format(LayoutDate),
+			before.Format(layoutTransition),
+			after,
+			tr.Description,
+			tr.OffsetChange))
+	}
+
+	return sb.String()
+}
+
+// ShowDSTList writes every transition for an IATA code's location in the
+// given year.
+func ShowDSTList(w io.Writer, iata string, year int, icons IconSet) {
+	result := LookupTime(iata, nil)
+	if result.Err != nil {
+		_, _ = fmt.Fprintf(w, "%s: %v\n", result.IATA, result.Err)
+		return
+	}
+	if !result.Found {
+		_, _ = fmt.Fprintf(w, "%s: Unknown airport code\n", result.IATA)
+		return
+	}
+
+	transitions := ListDSTTransitions(result.Time.Location(), year)
+	_, _ = fmt.Fprint(w, icons.symbols(FormatDSTList(result.IATA, result.Location, year, transitions)))
+}
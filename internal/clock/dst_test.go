@@ -1,6 +1,9 @@
 package clock
 
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
 	"testing"
 	"time"
 
@@ -95,6 +98,447 @@ func TestFindDSTTransition_NoTransitionZone(t *testing.T) {
 	assert.Nil(t, result, "Phoenix should have no DST transitions")
 }
 
+func TestFindDSTTransition_HalfHourShift(t *testing.T) {
+	// Lord Howe Island falls back from 02:00 LHDT (+11) to 01:30 LHST (+10:30)
+	loc, err := time.LoadLocation("Australia/Lord_Howe")
+	require.NoError(t, err)
+
+	beforeShift := time.Date(2024, 4, 5, 12, 0, 0, 0, loc)
+	result := FindDSTTransition(beforeShift, 5)
+
+	require.NotNil(t, result, "should find the 30-minute transition")
+	assert.Equal(t, "-0h30m", result.OffsetChange)
+	assert.True(t, result.Date.Equal(time.Date(2024, 4, 6, 15, 0, 0, 0, time.UTC)),
+		"transition should be at the exact instant, got %v", result.Date)
+	assert.Equal(t, 2, result.DaysUntil)
+}
+
+func TestFindDSTTransition_LargeWindow(t *testing.T) {
+	// With a 90-day window from mid-July, only the November transition is in range
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+
+	midSummer := time.Date(2024, 7, 15, 12, 0, 0, 0, loc)
+	result := FindDSTTransition(midSummer, 90)
+	assert.Nil(t, result, "no transition within 90 days of mid-July")
+
+	result = FindDSTTransition(midSummer, 120)
+	require.NotNil(t, result)
+	assert.Equal(t, "DST ends", result.Description)
+	assert.Equal(t, 111, result.DaysUntil)
+}
+
+func TestFindDSTTransition_NextOfSeveral(t *testing.T) {
+	// Two days after the clocks went forward, a one-year window also holds
+	// October's change; the next transition wins over the closer past one
+	loc, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	april := time.Date(2024, 4, 2, 12, 0, 0, 0, loc)
+	result := FindDSTTransition(april, 365)
+
+	require.NotNil(t, result)
+	assert.Equal(t, "DST ends", result.Description)
+	assert.Equal(t, time.October, result.Date.Month())
+	assert.Equal(t, 208, result.DaysUntil)
+
+	// With only past transitions in the window, the most recent one is returned
+	result = FindDSTTransition(april, 5)
+	require.NotNil(t, result)
+	assert.Equal(t, "DST starts", result.Description)
+	assert.Equal(t, -3, result.DaysUntil)
+}
+
+func TestFindDSTTransition_SkipsRuleChange(t *testing.T) {
+	// Turkey's switch from EEST to +03 in September 2016 kept the clocks where they were
+	loc, err := time.LoadLocation("Europe/Istanbul")
+	require.NoError(t, err)
+
+	assert.Nil(t, FindDSTTransition(time.Date(2016, 9, 6, 12, 0, 0, 0, loc), 5))
+}
+
+func TestListDSTTransitions_SameDay(t *testing.T) {
+	// No real zone has two transitions within a day, so build one that does
+	at := time.Date(2030, 6, 1, 6, 0, 0, 0, time.UTC)
+	loc := twoChangesInADay(t, at)
+
+	transitions := ListDSTTransitions(loc, 2030)
+	require.Len(t, transitions, 2)
+	assert.True(t, transitions[0].Date.Equal(at), "got %v", transitions[0].Date)
+	assert.Equal(t, "AAA", transitions[0].Before.Name)
+	assert.True(t, transitions[1].Date.Equal(at.Add(6*time.Hour)), "got %v", transitions[1].Date)
+	assert.Equal(t, "CCC", transitions[1].After.Name)
+}
+
+// twoChangesInADay returns a zone that moves from AAA (UTC) to BBB (+1, DST)
+// at the instant at, then to CCC (+2) six hours later.
+func twoChangesInADay(t *testing.T, at time.Time) *time.Location {
+	t.Helper()
+
+	var data bytes.Buffer
+	data.WriteString("TZif")
+	data.Write(make([]byte, 16))
+	for _, count := range []uint32{0, 0, 0, 2, 3, 12} { // isut, isstd, leap, time, type, char
+		require.NoError(t, binary.Write(&data, binary.BigEndian, count))
+	}
+	for _, tr := range []int32{int32(at.Unix()), int32(at.Add(6 * time.Hour).Unix())} {
+		require.NoError(t, binary.Write(&data, binary.BigEndian, tr))
+	}
+	data.Write([]byte{1, 2})
+	for _, zone := range []struct {
+		offset int32
+		dst    byte
+		abbr   byte
+	}{{0, 0, 0}, {3600, 1, 4}, {7200, 0, 8}} {
+		require.NoError(t, binary.Write(&data, binary.BigEndian, zone.offset))
+		data.Write([]byte{zone.dst, zone.abbr})
+	}
+	data.WriteString("AAA\x00BBB\x00CCC\x00")
+
+	loc, err := time.LoadLocationFromTZData("Test/Twice", data.Bytes())
+	require.NoError(t, err)
+	return loc
+}
+
+func TestFindDSTTransition_MatchesHourlyScan(t *testing.T) {
+	zones := []string{"America/Los_Angeles", "Europe/London", "Australia/Sydney", "America/Phoenix"}
+	for _, name := range zones {
+		loc, err := time.LoadLocation(name)
+		require.NoError(t, err)
+
+		for day := 0; day < 365; day += 3 {
+			ref := time.Date(2024, 1, 1, 12, 0, 0, 0, loc).AddDate(0, 0, day)
+			want := findDSTTransitionHourly(ref, DefaultDSTWindow)
+			got := FindDSTTransition(ref, DefaultDSTWindow)
+
+			if want == nil {
+				assert.Nil(t, got, "%s %s", name, ref)
+				continue
+			}
+			require.NotNil(t, got, "%s %s", name, ref)
+			// The hourly scan reports fall-back transitions at the first hour after
+			// the change, so it can be up to an hour late.
+			late := want.Date.Sub(got.Date)
+			assert.True(t, late >= 0 && late <= time.Hour, "%s %s: %v vs %v", name, ref, want.Date, got.Date)
+			assert.Equal(t, want.OffsetChange, got.OffsetChange, "%s %s", name, ref)
+		}
+	}
+}
+
+func BenchmarkFindDSTTransition(b *testing.B) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(b, err)
+	ref := time.Date(2024, 7, 15, 12, 0, 0, 0, loc)
+
+	for _, window := range []int{DefaultDSTWindow, 90, 365} {
+		b.Run(fmt.Sprintf("bisect/window=%d", window), func(b *testing.B) {
+			for b.Loop() {
+				FindDSTTransition(ref, window)
+			}
+		})
+		b.Run(fmt.Sprintf("hourly/window=%d", window), func(b *testing.B) {
+			for b.Loop() {
+				findDSTTransitionHourly(ref, window)
+			}
+		})
+	}
+}
+
+// findDSTTransitionHourly is the original FindDSTTransition, which probed every
+// hour of the window with time.Date. It is kept as a reference for benchmarks
+// and to check that the bisection search finds the same transitions.
+func findDSTTransitionHourly(t time.Time, windowDays int) *DSTTransition {
+	loc := t.Location()
+	if loc == time.UTC {
+		return nil // UTC has no DST
+	}
+
+	// Check each day in the window (both past and future)
+	startDay := t.AddDate(0, 0, -windowDays)
+
+	for day := 0; day <= windowDays*2; day++ {
+		checkDay := startDay.AddDate(0, 0, day)
+
+		// Check at the start of each hour on this day for a transition
+		for hour := 0; hour < 24; hour++ {
+			checkTime := time.Date(checkDay.Year(), checkDay.Month(), checkDay.Day(), hour, 0, 0, 0, loc)
+
+			// Skip if this time is too far from our reference time
+			hoursDiff := checkTime.Sub(t).Hours()
+			if hoursDiff < float64(-windowDays*24) || hoursDiff > float64(windowDays*24) {
+				continue
+			}
+
+			// Check if there's a transition at this hour by creating the previous hour explicitly.
+			// We must use time.Date rather than checkTime.Add(-time.Hour) because Add()
+			// preserves the same zone offset, hiding the transition.
+			var prevHour time.Time
+			if hour == 0 {
+				prevDay := checkDay.AddDate(0, 0, -1)
+				prevHour = time.Date(prevDay.Year(), prevDay.Month(), prevDay.Day(), 23, 0, 0, 0, loc)
+			} else {
+				prevHour = time.Date(checkDay.Year(), checkDay.Month(), checkDay.Day(), hour-1, 0, 0, 0, loc)
+			}
+
+			_, prevOffset := prevHour.Zone()
+			_, checkOffset := checkTime.Zone()
+
+			if prevOffset != checkOffset {
+				// Found a transition!
+				offsetDiff := checkOffset - prevOffset
+				kind := DSTEnd
+				if offsetDiff > 0 {
+					kind = DSTStart
+				}
+
+				// Calculate days until transition
+				var daysUntil int
+				if checkTime.After(t) {
+					hoursRemaining := checkTime.Sub(t).Hours()
+					daysUntil = int(hoursRemaining / 24)
+					if hoursRemaining > 0 && int(hoursRemaining)%24 > 0 {
+						daysUntil++ // Round up for future transitions
+					}
+				} else {
+					hoursAgo := t.Sub(checkTime).Hours()
+					daysUntil = -int(hoursAgo / 24)
+					if hoursAgo > 0 && int(hoursAgo)%24 > 0 {
+						daysUntil-- // Round down (more negative) for past transitions
+					}
+				}
+
+				// Only return if we're currently on the "currentOffset" side of the transition
+				// and the transition is in the future, OR we just passed it
+				if daysUntil >= -windowDays && daysUntil <= windowDays {
+					return &DSTTransition{
+						Date:         checkTime,
+						DaysUntil:    daysUntil,
+						OffsetChange: formatOffsetChange(offsetDiff),
+						Description:  dstDescription(kind),
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestListDSTTransitions(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	transitions := ListDSTTransitions(london, 2027)
+	require.Len(t, transitions, 2, "London has two transitions a year")
+
+	// EU transitions happen at 01:00 UTC on the last Sundays of March and October
+	assert.True(t, transitions[0].Date.Equal(time.Date(2027, 3, 28, 1, 0, 0, 0, time.UTC)))
+	assert.Equal(t, ZoneState{Name: "GMT", Offset: 0}, transitions[0].Before)
+	assert.Equal(t, ZoneState{Name: "BST", Offset: 3600, DST: true}, transitions[0].After)
+	assert.Equal(t, "+1h", transitions[0].OffsetChange)
+
+	assert.True(t, transitions[1].Date.Equal(time.Date(2027, 10, 31, 1, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "BST", transitions[1].Before.Name)
+	assert.Equal(t, "GMT", transitions[1].After.Name)
+	assert.Equal(t, "-1h", transitions[1].OffsetChange)
+}
+
+func TestListDSTTransitions_HalfHourShift(t *testing.T) {
+	// Lord Howe Island moves its clocks by 30 minutes
+	loc, err := time.LoadLocation("Australia/Lord_Howe")
+	require.NoError(t, err)
+
+	transitions := ListDSTTransitions(loc, 2024)
+	require.Len(t, transitions, 2)
+	assert.Equal(t, "-0h30m", transitions[0].OffsetChange)
+	assert.Equal(t, "+0h30m", transitions[1].OffsetChange)
+
+	// DST ends at 02:00 local daylight time on the first Sunday in April
+	assert.True(t, transitions[0].Date.Equal(time.Date(2024, 4, 6, 15, 0, 0, 0, time.UTC)))
+}
+
+func TestListDSTTransitions_NoTransitions(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	assert.Empty(t, ListDSTTransitions(loc, 2027))
+	assert.Empty(t, ListDSTTransitions(time.UTC, 2027))
+}
+
+func TestFormatDSTList(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	got := FormatDSTList("LON", "Europe/London", 2027, ListDSTTransitions(london, 2027))
+	assert.Contains(t, got, "LON: 2 transitions in 2027 (Europe/London)")
+	assert.Contains(t, got, "Sun Mar 28 01:00:00 GMT +00:00 → 02:00:00 BST +01:00")
+	assert.Contains(t, got, "Sun Oct 31 02:00:00 BST +01:00 → 01:00:00 GMT +00:00")
+
+	got = FormatDSTList("NRT", "Asia/Tokyo", 2027, nil)
+	assert.Equal(t, "NRT: no transitions in 2027 (Asia/Tokyo)\n", got)
+}
+
+func TestFormatDSTList_DateLine(t *testing.T) {
+	// Samoa skipped December 30, 2011 by moving across the date line
+	apia, err := time.LoadLocation("Pacific/Apia")
+	require.NoError(t, err)
+
+	got := FormatDSTList("APW", "Pacific/Apia", 2011, ListDSTTransitions(apia, 2011))
+	assert.Contains(t, got, "Fri Dec 30 00:00:00 -10 -10:00 → Sat Dec 31 00:00:00 +14 +14:00")
+}
+
+func TestShowDSTList(t *testing.T) {
+	var buf bytes.Buffer
+	ShowDSTList(&buf, "lon", 2027, IconsClock)
+	assert.Contains(t, buf.String(), "LON: 2 transitions in 2027")
+	assert.Contains(t, buf.String(), " → ")
+
+	buf.Reset()
+	ShowDSTList(&buf, "lon", 2027, IconsASCII)
+	assert.Contains(t, buf.String(), " -> ")
+	assertASCII(t, buf.String())
+
+	buf.Reset()
+	ShowDSTList(&buf, "XXX", 2027, IconsClock)
+	assert.Contains(t, buf.String(), "XXX: Unknown airport code")
+}
+
+func TestFindDSTTransition_SouthernHemisphere(t *testing.T) {
+	// Sydney's DST starts in October and ends in April
+	loc, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+
+	result := FindDSTTransition(time.Date(2024, 10, 4, 12, 0, 0, 0, loc), 5)
+	require.NotNil(t, result)
+	assert.Equal(t, DSTStart, result.Kind)
+	assert.Equal(t, "DST starts", result.Description)
+	assert.Equal(t, "+1h", result.OffsetChange)
+
+	result = FindDSTTransition(time.Date(2024, 4, 5, 12, 0, 0, 0, loc), 5)
+	require.NotNil(t, result)
+	assert.Equal(t, DSTEnd, result.Kind)
+	assert.Equal(t, "DST ends", result.Description)
+}
+
+func TestFindDSTTransition_NegativeDST(t *testing.T) {
+	// The tz database models Irish winter time (GMT) as negative DST, so the
+	// DST flag is set in winter; the descriptions should still follow summer time.
+	loc, err := time.LoadLocation("Europe/Dublin")
+	require.NoError(t, err)
+
+	result := FindDSTTransition(time.Date(2024, 3, 28, 12, 0, 0, 0, loc), 5)
+	require.NotNil(t, result)
+	assert.Equal(t, "DST starts", result.Description)
+	assert.Equal(t, "+1h", result.OffsetChange)
+
+	result = FindDSTTransition(time.Date(2024, 10, 25, 12, 0, 0, 0, loc), 5)
+	require.NotNil(t, result)
+	assert.Equal(t, "DST ends", result.Description)
+	assert.Equal(t, "-1h", result.OffsetChange)
+}
+
+func TestFindDSTTransition_PermanentOffsetChange(t *testing.T) {
+	// Samoa moved from UTC-10 to UTC+14 on December 30, 2011 while on DST
+	loc, err := time.LoadLocation("Pacific/Apia")
+	require.NoError(t, err)
+
+	result := FindDSTTransition(time.Date(2011, 12, 28, 12, 0, 0, 0, loc), 5)
+	require.NotNil(t, result)
+	assert.Equal(t, PermanentOffsetChange, result.Kind)
+	assert.True(t, result.Kind.Permanent())
+	assert.Equal(t, "Permanent offset change", result.Description)
+	assert.Equal(t, "+24h", result.OffsetChange)
+
+	warning := FormatDSTWarning(result)
+	assert.Contains(t, warning, "Permanent offset change in 2 days")
+	assert.Contains(t, warning, "-10 → +14")
+}
+
+func TestListDSTTransitions_DoubleSummerTime(t *testing.T) {
+	// Britain moved from BST to BDST and back each summer during the war.
+	// Both are DST, but the changes are seasonal, not permanent.
+	loc, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	var got []string
+	for _, tr := range ListDSTTransitions(loc, 1941) {
+		got = append(got, tr.Before.Name+" → "+tr.After.Name+" "+tr.Description)
+	}
+	assert.Equal(t, []string{"BST → BDST DST starts", "BDST → BST DST ends"}, got)
+
+	result := FindDSTTransition(time.Date(1941, 5, 2, 12, 0, 0, 0, time.UTC).In(loc), 5)
+	require.NotNil(t, result)
+	assert.Equal(t, DSTStart, result.Kind)
+	assert.Contains(t, FormatDSTWarning(result), "DST starts in 2 days (+1h)")
+}
+
+func TestFindDSTTransition_RuleChange(t *testing.T) {
+	// Turkey stayed on UTC+3 when it abolished DST in 2016
+	loc, err := time.LoadLocation("Europe/Istanbul")
+	require.NoError(t, err)
+
+	transitions := ListDSTTransitions(loc, 2016)
+	require.NotEmpty(t, transitions)
+	last := transitions[len(transitions)-1]
+	assert.Equal(t, RuleChange, last.Kind)
+	assert.Equal(t, "+0h", last.OffsetChange)
+	assert.Contains(t, FormatDSTWarning(&last), "EEST → +03")
+}
+
+func TestClassifyTransition(t *testing.T) {
+	std := ZoneState{Name: "PST", Offset: -8 * 3600}
+	dst := ZoneState{Name: "PDT", Offset: -7 * 3600, DST: true}
+
+	tests := []struct {
+		name     string
+		before   ZoneState
+		after    ZoneState
+		reversed bool
+		want     TransitionKind
+	}{
+		{name: "spring forward", before: std, after: dst, want: DSTStart},
+		{name: "fall back", before: dst, after: std, want: DSTEnd},
+		{
+			name:   "negative DST ends in spring",
+			before: ZoneState{Name: "GMT", Offset: 0, DST: true},
+			after:  ZoneState{Name: "IST", Offset: 3600},
+			want:   DSTStart,
+		},
+		{
+			name:   "standard offset moves",
+			before: ZoneState{Name: "MSK", Offset: 3 * 3600},
+			after:  ZoneState{Name: "MSK", Offset: 4 * 3600},
+			want:   PermanentOffsetChange,
+		},
+		{
+			name:     "double summer time starts",
+			before:   ZoneState{Name: "BST", Offset: 3600, DST: true},
+			after:    ZoneState{Name: "BDST", Offset: 2 * 3600, DST: true},
+			reversed: true,
+			want:     DSTStart,
+		},
+		{
+			name:     "double summer time ends",
+			before:   ZoneState{Name: "BDST", Offset: 2 * 3600, DST: true},
+			after:    ZoneState{Name: "BST", Offset: 3600, DST: true},
+			reversed: true,
+			want:     DSTEnd,
+		},
+		{
+			name:   "abbreviation only",
+			before: ZoneState{Name: "EEST", Offset: 3 * 3600, DST: true},
+			after:  ZoneState{Name: "+03", Offset: 3 * 3600},
+			want:   RuleChange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyTransition(tt.before, tt.after, tt.reversed))
+		})
+	}
+}
+
 func TestFormatOffsetChange(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,116 @@
+package clock
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cv/t/internal/tzdata"
+)
+
+// GroupMode selects how ShowAll collapses codes into one line.
+type GroupMode int
+
+const (
+	// GroupNone shows every code on its own line.
+	GroupNone GroupMode = iota
+	// GroupZone collapses codes that resolve to the same IANA zone.
+	GroupZone
+	// GroupOffset collapses codes whose zones currently have the same UTC offset.
+	GroupOffset
+)
+
+// ParseGroupMode parses a --group value: "zone" (or "") or "offset".
+func ParseGroupMode(s string) (GroupMode, error) {
+	switch strings.ToLower(s) {
+	case "", "zone":
+		return GroupZone, nil
+	case "offset":
+		return GroupOffset, nil
+	default:
+		return GroupNone, fmt.Errorf("invalid group mode: %s (use zone or offset)", s)
+	}
+}
+
+// GroupResults collapses found results that share a zone or offset into one
+// result whose IATA is the codes joined with "/", e.g. "SFO/LAX/SJC", and
+// whose Location lists the distinct zones. Groups keep the position of their
+// first code; unknown codes are never grouped.
+//
+// Zones are compared by their current names, so Asia/Calcutta and
+// Asia/Kolkata group together. When offsets are grouped and showDST is set,
+// zones only group if they also have the same upcoming transition within
+// dstWindow days, so each line's DST warning is right for all of its codes.
+func GroupResults(results []TimeResult, mode GroupMode, showDST bool, dstWindow int) []TimeResult {
+	if mode == GroupNone {
+		return results
+	}
+
+	var grouped []TimeResult
+	var members [][]TimeResult
+	index := make(map[string]int)
+
+	for _, r := range results {
+		if !r.Found {
+			grouped = append(grouped, r)
+			members = append(members, nil)
+			continue
+		}
+
+		key := groupKey(r, mode, showDST, dstWindow)
+		if i, ok := index[key]; ok {
+			members[i] = append(members[i], r)
+			continue
+		}
+		index[key] = len(grouped)
+		grouped = append(grouped, r)
+		members = append(members, []TimeResult{r})
+	}
+
+	for i, group := range members {
+		if len(group) > 1 {
+			grouped[i] = mergeGroup(group)
+		}
+	}
+
+	return grouped
+}
+
+// groupKey returns the key results are grouped by.
+func groupKey(r TimeResult, mode GroupMode, showDST bool, dstWindow int) string {
+	if mode == GroupZone {
+		return tzdata.Canonical(r.Location)
+	}
+
+	_, offset := r.Time.Zone()
+	key := fmt.Sprint(offset)
+	if showDST {
+		if tr := FindDSTTransition(r.Time, dstWindow); tr != nil {
+			key += fmt.Sprintf(" %s %s", tr.Date.UTC().Format("2006-01-02T15:04"), tr.OffsetChange)
+		}
+	}
+	return key
+}
+
+// mergeGroup combines the results of a group into one, using the first
+// result's time. Repeated codes are listed once.
+func mergeGroup(group []TimeResult) TimeResult {
+	merged := group[0]
+
+	var iatas, locations []string
+	seenIATA := make(map[string]bool)
+	seenLocation := make(map[string]bool)
+	for _, r := range group {
+		if !seenIATA[r.IATA] {
+			seenIATA[r.IATA] = true
+			iatas = append(iatas, r.IATA)
+		}
+		if !seenLocation[r.Location] {
+			seenLocation[r.Location] = true
+			locations = append(locations, r.Location)
+		}
+	}
+
+	merged.IATA = strings.Join(iatas, "/")
+	merged.Location = strings.Join(locations, ", ")
+	return merged
+}
@@ -0,0 +1,131 @@
+package clock
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGroupMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    GroupMode
+		wantErr bool
+	}{
+		{"", GroupZone, false},
+		{"zone", GroupZone, false},
+		{"offset", GroupOffset, false},
+		{"OFFSET", GroupOffset, false},
+		{"country", GroupNone, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseGroupMode(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// lookupAll looks up codes at a fixed time.
+func lookupAll(iatas []string, now time.Time) []TimeResult {
+	results := make([]TimeResult, len(iatas))
+	for i, iata := range iatas {
+		results[i] = LookupTime(iata, &now)
+	}
+	return results
+}
+
+// groupedIATAs returns the IATA field of each result.
+func groupedIATAs(results []TimeResult) []string {
+	iatas := make([]string, len(results))
+	for i, r := range results {
+		iatas[i] = r.IATA
+	}
+	return iatas
+}
+
+func TestGroupResults_Zone(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	results := lookupAll([]string{"SFO", "JFK", "LAX", "XXX", "SJC", "EWR"}, now)
+
+	got := GroupResults(results, GroupZone, false, DefaultDSTWindow)
+	assert.Equal(t, []string{"SFO/LAX/SJC", "JFK/EWR", "XXX"}, groupedIATAs(got))
+	assert.Equal(t, "America/Los_Angeles", got[0].Location)
+	assert.False(t, got[2].Found, "unknown codes stay on their own line")
+}
+
+func TestGroupResults_None(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	results := lookupAll([]string{"SFO", "LAX"}, now)
+
+	assert.Equal(t, results, GroupResults(results, GroupNone, false, DefaultDSTWindow))
+}
+
+func TestGroupResults_Duplicates(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	results := lookupAll([]string{"SFO", "sfo", "LAX"}, now)
+
+	got := GroupResults(results, GroupZone, false, DefaultDSTWindow)
+	assert.Equal(t, []string{"SFO/LAX"}, groupedIATAs(got))
+}
+
+func TestGroupResults_DeprecatedZoneName(t *testing.T) {
+	useLocations(t, map[string]string{"BLR1": "Asia/Kolkata"})
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	results := lookupAll([]string{"BOM", "BLR1"}, now) // BOM is Asia/Calcutta
+
+	got := GroupResults(results, GroupZone, false, DefaultDSTWindow)
+	require.Len(t, got, 1)
+	assert.Equal(t, "BOM/BLR1", got[0].IATA)
+	assert.Equal(t, "Asia/Calcutta, Asia/Kolkata", got[0].Location)
+}
+
+func TestGroupResults_Offset(t *testing.T) {
+	// In June, London (BST) and Lagos (WAT) are both UTC+1; Paris is UTC+2
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	results := lookupAll([]string{"LHR", "CDG", "LOS", "FRA"}, now)
+
+	got := GroupResults(results, GroupOffset, false, DefaultDSTWindow)
+	assert.Equal(t, []string{"LHR/LOS", "CDG/FRA"}, groupedIATAs(got))
+	assert.Equal(t, "Europe/London, Africa/Lagos", got[0].Location)
+
+	zones := GroupResults(results, GroupZone, false, DefaultDSTWindow)
+	assert.Len(t, zones, 4)
+}
+
+func TestGroupResults_OffsetSplitsOnDSTWarning(t *testing.T) {
+	// Two days before London's DST ends, London and Lagos share an offset but
+	// only London is about to change
+	now := time.Date(2024, 10, 25, 12, 0, 0, 0, time.UTC)
+	results := lookupAll([]string{"LHR", "LOS"}, now)
+
+	assert.Len(t, GroupResults(results, GroupOffset, false, DefaultDSTWindow), 1)
+	assert.Len(t, GroupResults(results, GroupOffset, true, DefaultDSTWindow), 2)
+}
+
+func TestShowAll_Group(t *testing.T) {
+	now := time.Date(2024, 6, 15, 23, 6, 21, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowAll(&buf, []string{"SFO", "LAX", "SJC", "JFK"}, DisplayOptions{Group: GroupZone}, &now)
+
+	output := buf.String()
+	assert.Contains(t, output, "SFO/LAX/SJC: ")
+	assert.Contains(t, output, "16:06:21")
+	assert.Contains(t, output, "(America/Los_Angeles)\n")
+	assert.Contains(t, output, "JFK: ")
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("\n")))
+
+	buf.Reset()
+	ShowAll(&buf, []string{"SFO", "LAX", "JFK"}, DisplayOptions{PS1Format: true, Group: GroupZone}, &now)
+	assert.Equal(t, "SFO/LAX 16:06 JFK 19:06", buf.String())
+}
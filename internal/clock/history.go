@@ -0,0 +1,178 @@
+package clock
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cv/t/internal/tzdata"
+)
+
+// instantLayouts are the timestamp formats accepted by ParseInstant, most specific first.
+// Layouts without a zone are interpreted as UTC.
+var instantLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// ParseInstant parses a timestamp such as "1995-06-01T12:00Z",
+// "1995-06-01T12:00:00+02:00" or "1995-06-01" (midnight UTC).
+func ParseInstant(s string) (time.Time, error) {
+	for _, layout := range instantLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp: %s (use YYYY-MM-DDTHH:MMZ or YYYY-MM-DD)", s)
+}
+
+// RuleHistory describes how a location's zone at a past instant differs from
+// the zone it uses on the same calendar date today.
+type RuleHistory struct {
+	// At is the past instant in the location's time zone
+	At time.Time
+	// Then is the zone in effect at At
+	Then ZoneState
+	// Today is the zone in effect on the same date and time this year
+	Today ZoneState
+	// Changes are the permanent transitions between At and the same date today
+	Changes []DSTTransition
+	// Differs reports whether the rules at At differ from today's: Then and
+	// Today differ, and either their standard offsets differ or Changes is
+	// not empty. A DST change falling on a different date this year doesn't
+	// count.
+	Differs bool
+	// Zone is the location's zone name
+	Zone string
+	// Renamed is Zone's current name if the tz database has since renamed
+	// it, such as Europe/Kyiv for Europe/Kiev
+	Renamed string
+}
+
+// CompareRules checks whether loc used different rules at the instant at than
+// it does on the same calendar date in now's year, or has been renamed since.
+// Seasonal differences don't count: summer is compared with summer, and a
+// date that falls on the other side of a DST change in one of the years is
+// only reported if the standard offset or a permanent transition changed.
+// Feb 29 is compared with Feb 28 in other years. Returns nil if the rules
+// match and the zone's name is current.
+func CompareRules(loc *time.Location, at, now time.Time) *RuleHistory {
+	at = at.In(loc)
+	now = now.In(loc)
+	day := min(at.Day(), daysIn(at.Month(), now.Year()))
+	sameDateToday := time.Date(now.Year(), at.Month(), day, at.Hour(), at.Minute(), at.Second(), 0, loc)
+
+	history := &RuleHistory{At: at, Then: zoneState(at), Today: zoneState(sameDateToday), Zone: loc.String()}
+	if current, ok := tzdata.Deprecated(history.Zone); ok {
+		history.Renamed = current
+	}
+
+	if history.Then.Offset != history.Today.Offset || history.Then.Name != history.Today.Name {
+		from, to := at, sameDateToday
+		if to.Before(from) {
+			from, to = to, from
+		}
+		for _, tr := range transitionsBetween(loc, from, to) {
+			if tr.Kind.Permanent() {
+				history.Changes = append(history.Changes, tr)
+			}
+		}
+		history.Differs = len(history.Changes) > 0 || standardOffset(at) != standardOffset(sameDateToday)
+	}
+
+	if !history.Differs {
+		if history.Renamed == "" {
+			return nil
+		}
+		history.Changes = nil
+	}
+	return history
+}
+
+// standardOffset returns the offset of t's location outside DST around t:
+// the offset at t itself, or if that is DST, the offset after DST next ends
+// or before it last started, within a year. Zones that stay on DST for
+// longer than that use the offset at t.
+func standardOffset(t time.Time) int {
+	state := zoneState(t)
+	if !state.DST {
+		return state.Offset
+	}
+
+	const year = 366 * 24 * time.Hour
+	for _, tr := range transitionsBetween(t.Location(), t, t.Add(year)) {
+		if !tr.After.DST {
+			return tr.After.Offset
+		}
+	}
+	before := transitionsBetween(t.Location(), t.Add(-year), t)
+	for i := len(before) - 1; i >= 0; i-- {
+		if !before[i].Before.DST {
+			return before[i].Before.Offset
+		}
+	}
+	return state.Offset
+}
+
+// daysIn returns the number of days in a month.
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// FormatRuleHistory formats a RuleHistory for display.
+func FormatRuleHistory(iata string, h *RuleHistory) string {
+	var sb strings.Builder
+
+	if h.Differs {
+		sb.WriteString(fmt.Sprintf("%s: rules differed on %s: then %s (UTC%s), today %s (UTC%s)\n",
+			iata, h.At.Format("2006-01-02"),
+			h.Then.Name, formatUTCOffset(h.Then.Offset),
+			h.Today.Name, formatUTCOffset(h.Today.Offset)))
+	}
+	if h.Renamed != "" {
+		sb.WriteString(fmt.Sprintf("%s: zone %s has been renamed %s\n", iata, h.Zone, h.Renamed))
+	}
+
+	for _, tr := range h.Changes {
+		sb.WriteString(fmt.Sprintf("  %s %s (%s, %s → %s)\n",
+			tr.Date.Format("2006-01-02"), tr.Description, tr.OffsetChange, tr.Before.Name, tr.After.Name))
+	}
+
+	return sb.String()
+}
+
+// ShowRuleHistory writes a note for each IATA code whose zone rules at the
+// instant at differ from today's, or whose zone has been renamed. Other codes
+// print nothing. If now is nil, the current time is used.
+func ShowRuleHistory(w io.Writer, iatas []string, at time.Time, icons IconSet, now *time.Time) {
+	var today time.Time
+	if now != nil {
+		today = *now
+	} else {
+		today = time.Now()
+	}
+
+	for _, iata := range iatas {
+		result := LookupTime(iata, &at)
+		if !result.Found {
+			continue
+		}
+		if h := CompareRules(result.Time.Location(), at, today); h != nil {
+			_, _ = fmt.Fprint(w, icons.symbols(FormatRuleHistory(result.IATA, h)))
+		}
+	}
+}
+
+// formatUTCOffset formats an offset in seconds as "+05:30" or "-10:00".
+func formatUTCOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, offset/3600, (offset%3600)/60)
+}
@@ -0,0 +1,158 @@
+package clock
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInstant(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "minutes with Z", input: "1995-06-01T12:00Z", want: time.Date(1995, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{name: "RFC 3339", input: "1995-06-01T12:00:30Z", want: time.Date(1995, 6, 1, 12, 0, 30, 0, time.UTC)},
+		{name: "numeric offset", input: "1995-06-01T14:00+02:00", want: time.Date(1995, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{name: "no zone is UTC", input: "1995-06-01T12:00", want: time.Date(1995, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{name: "date only", input: "1995-06-01", want: time.Date(1995, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "garbage", input: "yesterday", wantErr: true},
+		{name: "invalid date", input: "1995-13-01", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseInstant(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want, got)
+		})
+	}
+}
+
+func TestCompareRules_Unchanged(t *testing.T) {
+	// London's rules in 1995 summer match today's summer rules
+	loc, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	at := time.Date(1995, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	assert.Nil(t, CompareRules(loc, at, now), "summer 1995 should compare with summer today")
+}
+
+func TestCompareRules_DSTDateMoved(t *testing.T) {
+	// BST started on 1995-03-26 but starts on 2026-03-29, so London was on
+	// BST on 1995-03-27 and is on GMT on that date in 2026. The rules are
+	// the same; only the calendar date of the change moved.
+	loc, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	at := time.Date(1995, 3, 27, 12, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	require.NotEqual(t, zoneState(at.In(loc)).Name, zoneState(time.Date(2026, 3, 27, 12, 0, 0, 0, loc)).Name)
+	assert.Nil(t, CompareRules(loc, at, now))
+}
+
+func TestCompareRules_DateLine(t *testing.T) {
+	// Samoa was UTC-11 in mid-2011 and moved across the date line that December
+	loc, err := time.LoadLocation("Pacific/Apia")
+	require.NoError(t, err)
+
+	at := time.Date(2011, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	h := CompareRules(loc, at, now)
+
+	require.NotNil(t, h)
+	assert.Equal(t, -11*3600, h.Then.Offset)
+	assert.Equal(t, 13*3600, h.Today.Offset)
+	require.Len(t, h.Changes, 1, "only the date line crossing is permanent")
+	assert.Equal(t, PermanentOffsetChange, h.Changes[0].Kind)
+	assert.Equal(t, "+24h", h.Changes[0].OffsetChange)
+}
+
+func TestCompareRules_Moscow(t *testing.T) {
+	// Moscow used UTC+4 all year from 2011 to 2014
+	loc, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	at := time.Date(2012, 1, 15, 12, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	h := CompareRules(loc, at, now)
+
+	require.NotNil(t, h)
+	assert.Equal(t, 4*3600, h.Then.Offset)
+	assert.Equal(t, 3*3600, h.Today.Offset)
+	require.Len(t, h.Changes, 1)
+	assert.Equal(t, "-1h", h.Changes[0].OffsetChange)
+}
+
+func TestCompareRules_LeapDay(t *testing.T) {
+	// Asuncion left summer time on 1977-03-01, so Feb 29 1976 must be
+	// compared with Feb 28 1977 rather than rolling over to Mar 1
+	loc, err := time.LoadLocation("America/Asuncion")
+	require.NoError(t, err)
+
+	at := time.Date(1976, 2, 29, 12, 0, 0, 0, loc)
+	now := time.Date(1977, 6, 1, 12, 0, 0, 0, loc)
+	assert.Nil(t, CompareRules(loc, at, now))
+}
+
+func TestCompareRules_Renamed(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Kiev")
+	require.NoError(t, err)
+
+	at := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	h := CompareRules(loc, at, now)
+
+	require.NotNil(t, h, "a renamed zone is reported even if its rules match")
+	assert.Equal(t, "Europe/Kiev", h.Zone)
+	assert.Equal(t, "Europe/Kyiv", h.Renamed)
+	assert.Equal(t, "KBP: zone Europe/Kiev has been renamed Europe/Kyiv\n", FormatRuleHistory("KBP", h))
+}
+
+func TestFormatRuleHistory(t *testing.T) {
+	loc, err := time.LoadLocation("Pacific/Apia")
+	require.NoError(t, err)
+
+	at := time.Date(2011, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	got := FormatRuleHistory("APW", CompareRules(loc, at, now))
+
+	assert.Contains(t, got, "APW: rules differed on 2011-06-01: then -11 (UTC-11:00), today +13 (UTC+13:00)")
+	assert.Contains(t, got, "Permanent offset change (+24h, -10 → +14)")
+}
+
+func TestShowRuleHistory(t *testing.T) {
+	at := time.Date(2011, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowRuleHistory(&buf, []string{"SFO", "APW", "XXX"}, at, IconsClock, &now)
+	got := buf.String()
+
+	assert.Contains(t, got, "APW: rules differed")
+	assert.NotContains(t, got, "SFO", "SFO's rules have not changed")
+	assert.NotContains(t, got, "XXX", "unknown codes are skipped")
+
+	buf.Reset()
+	ShowRuleHistory(&buf, []string{"APW"}, at, IconsASCII, &now)
+	assert.Contains(t, buf.String(), "(+24h, -10 -> +14)")
+	assertASCII(t, buf.String())
+}
+
+func TestFormatUTCOffset(t *testing.T) {
+	assert.Equal(t, "+00:00", formatUTCOffset(0))
+	assert.Equal(t, "+05:30", formatUTCOffset(5*3600+1800))
+	assert.Equal(t, "-10:00", formatUTCOffset(-10*3600))
+	assert.Equal(t, "-03:30", formatUTCOffset(-3*3600-1800))
+}
@@ -0,0 +1,126 @@
+package clock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// IconSet selects the icons shown next to times.
+type IconSet int
+
+const (
+	// IconsClock shows the clock face nearest the time (the default)
+	IconsClock IconSet = iota
+	// IconsDayNight shows a sun during the day and a moon at night
+	IconsDayNight
+	// IconsASCII shows "*" during the day and "." at night, and replaces
+	// the other non-ASCII symbols in the output
+	IconsASCII
+	// IconsNone shows no icons
+	IconsNone
+)
+
+// dayHours are the hours IconsDayNight and IconsASCII show as day.
+var dayHours = WorkHours{Start: 6, End: 18}
+
+// asciiReplacer replaces the symbols used outside icons in ASCII mode.
+var asciiReplacer = strings.NewReplacer("⚠️", "!", "→", "->")
+
+// symbols replaces the symbols in s with ASCII ones if the set is IconsASCII.
+func (set IconSet) symbols(s string) string {
+	if set != IconsASCII {
+		return s
+	}
+	return asciiReplacer.Replace(s)
+}
+
+// ParseIconSet parses an --icons value: clock, daynight, ascii or none.
+func ParseIconSet(s string) (IconSet, error) {
+	switch strings.ToLower(s) {
+	case "clock", "":
+		return IconsClock, nil
+	case "daynight", "day-night":
+		return IconsDayNight, nil
+	case "ascii":
+		return IconsASCII, nil
+	case "none":
+		return IconsNone, nil
+	default:
+		return IconsClock, fmt.Errorf("invalid icon set: %s (use clock, daynight, ascii or none)", s)
+	}
+}
+
+// Icon returns the icon for a local time, or "" for IconsNone.
+func (s IconSet) Icon(t time.Time) string {
+	day := dayHours.Contains(t.Hour())
+	switch s {
+	case IconsDayNight:
+		if day {
+			return "🌞"
+		}
+		return "🌙"
+	case IconsASCII:
+		if day {
+			return "*"
+		}
+		return "."
+	case IconsNone:
+		return ""
+	default:
+		return ClockEmoji(t)
+	}
+}
+
+// prefix returns the icon for t followed by a space, or "" for IconsNone.
+func (s IconSet) prefix(t time.Time) string {
+	if icon := s.Icon(t); icon != "" {
+		return icon + " "
+	}
+	return ""
+}
+
+// displayWidth returns the number of terminal columns s takes up, counting
+// emoji and East Asian wide characters as two columns.
+func displayWidth(s string) int {
+	width, last := 0, 0
+	for _, r := range s {
+		w := runeWidth(r)
+		if r == 0xFE0F && last == 1 {
+			// Emoji presentation, as in "⚠️", widens a narrow symbol
+			w = 1
+		}
+		width += w
+		last = w
+	}
+	return width
+}
+
+// runeWidth returns the number of terminal columns r takes up.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0x200D, r >= 0xFE00 && r <= 0xFE0F, unicode.Is(unicode.Mn, r):
+		// Zero-width joiners, variation selectors and combining marks
+		return 0
+	case r >= 0x1F300 && r <= 0x1FAFF,
+		r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// padRight pads s with spaces to width terminal columns.
+func padRight(s string, width int) string {
+	if pad := width - displayWidth(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
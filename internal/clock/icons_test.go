@@ -0,0 +1,126 @@
+package clock
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIconSet(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    IconSet
+		wantErr bool
+	}{
+		{"", IconsClock, false},
+		{"clock", IconsClock, false},
+		{"daynight", IconsDayNight, false},
+		{"Day-Night", IconsDayNight, false},
+		{"ascii", IconsASCII, false},
+		{"none", IconsNone, false},
+		{"emoji", IconsClock, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseIconSet(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIconSet_Icon(t *testing.T) {
+	noon := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	midnight := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, ClockEmoji(noon), IconsClock.Icon(noon))
+	assert.Equal(t, "🌞", IconsDayNight.Icon(noon))
+	assert.Equal(t, "🌙", IconsDayNight.Icon(midnight))
+	assert.Equal(t, "*", IconsASCII.Icon(noon))
+	assert.Equal(t, ".", IconsASCII.Icon(midnight))
+	assert.Equal(t, "", IconsNone.Icon(noon))
+}
+
+func TestIconSet_Width(t *testing.T) {
+	// Every icon in a set must take up the same number of columns, or rows
+	// won't line up
+	want := map[IconSet]int{IconsClock: 2, IconsDayNight: 2, IconsASCII: 1, IconsNone: 0}
+	for set, width := range want {
+		for minute := 0; minute < 24*60; minute += 15 {
+			ts := time.Date(2024, 1, 15, 0, minute, 0, 0, time.UTC)
+			assert.Equal(t, width, displayWidth(set.Icon(ts)), "icon set %d at %s", set, ts.Format("15:04"))
+		}
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	assert.Equal(t, 3, displayWidth("SFO"))
+	assert.Equal(t, 2, displayWidth("🕓"))
+	assert.Equal(t, 2, displayWidth("⚠️"), "variation selectors take no space")
+	assert.Equal(t, 6, displayWidth("東京駅"))
+	assert.Equal(t, 4, displayWidth("café"))
+	assert.Equal(t, 4, displayWidth("cafe\u0301"))
+}
+
+func TestPadRight(t *testing.T) {
+	assert.Equal(t, "SFO:   ", padRight("SFO:", 7))
+	assert.Equal(t, "東京: ", padRight("東京:", 6))
+	assert.Equal(t, "SFO/LAX:", padRight("SFO/LAX:", 4))
+}
+
+func TestShowAll_Alignment(t *testing.T) {
+	now := time.Date(2024, 1, 15, 17, 6, 21, 0, time.UTC)
+
+	for _, icons := range []IconSet{IconsClock, IconsDayNight, IconsASCII, IconsNone} {
+		var buf bytes.Buffer
+		ShowAll(&buf, []string{"SFO", "LAX", "LHR"}, DisplayOptions{Group: GroupZone, Icons: icons}, &now)
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		require.Len(t, lines, 2)
+
+		// Times start in the same column
+		col := func(line string) int { return displayWidth(line[:strings.Index(line, ":0")-2]) }
+		assert.Equal(t, col(lines[0]), col(lines[1]), "icon set %d:\n%s", icons, buf.String())
+		assert.True(t, strings.HasPrefix(lines[1], "LHR:     "), lines[1])
+	}
+}
+
+// assertASCII fails the test if s contains any non-ASCII rune.
+func assertASCII(t *testing.T, s string) {
+	t.Helper()
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			assert.Failf(t, "non-ASCII output", "%q in:\n%s", r, s)
+			return
+		}
+	}
+}
+
+func TestShowAll_ASCII(t *testing.T) {
+	now := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowAll(&buf, []string{"SFO"}, DisplayOptions{Icons: IconsASCII, ShowDST: true, DSTWindow: 10, From: time.UTC}, &now)
+	assert.Equal(t, "SFO: * 10:00:00 (-8h) (America/Los_Angeles) ! DST starts in 6 days (+1h)\n", buf.String())
+}
+
+func TestFormatConversion_Icons(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowConversion(&buf, TimeSpec{IATA: "SFO", Hour: 20}, []string{"NRT"}, DisplayOptions{Icons: IconsASCII}, &now)
+	assert.Equal(t, "SFO: . 20:00 Mon Jan 15  ->  NRT: * 13:00 Tue Jan 16 (+17h)\n", buf.String())
+
+	buf.Reset()
+	ShowConversion(&buf, TimeSpec{IATA: "SFO", Hour: 9}, []string{"JFK"}, DisplayOptions{Icons: IconsNone}, &now)
+	assert.Equal(t, "SFO: 09:00  →  JFK: 12:00 (+3h)\n", buf.String())
+}
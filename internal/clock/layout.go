@@ -0,0 +1,76 @@
+package clock
+
+import (
+	"fmt"
+	"time"
+)
+
+// Layouts are the time.Format layouts used to display times and dates.
+// Empty fields fall back to DefaultLayouts.
+type Layouts struct {
+	// Full is the time with seconds, used in the main display
+	Full string
+	// Short is the time without seconds, used for conversions, overlaps and PS1 output
+	Short string
+	// Date is shown alongside the time when dates are displayed
+	Date string
+}
+
+// DefaultLayouts are the 24-hour layouts t has always used.
+var DefaultLayouts = Layouts{Full: LayoutFull, Short: LayoutShort, Date: LayoutDate}
+
+// Layouts12Hour are 12-hour clock layouts, e.g. "4:06:21 PM".
+var Layouts12Hour = Layouts{Full: "3:04:05 PM", Short: "3:04 PM", Date: LayoutDate}
+
+// layoutCheckTime is formatted to check that a layout has time or date elements.
+var layoutCheckTime = time.Date(2001, 2, 3, 16, 5, 6, 0, time.UTC)
+
+// NewLayouts builds display layouts from the --12h, --layout and
+// --date-layout options. A custom time layout, such as "Mon 3:04PM", replaces
+// both the full and short layouts; empty layouts keep the defaults.
+func NewLayouts(twelveHour bool, timeLayout, dateLayout string) (Layouts, error) {
+	layouts := DefaultLayouts
+	if twelveHour {
+		layouts = Layouts12Hour
+	}
+
+	if timeLayout != "" {
+		if err := validateLayout(timeLayout); err != nil {
+			return Layouts{}, err
+		}
+		layouts.Full = timeLayout
+		layouts.Short = timeLayout
+	}
+
+	if dateLayout != "" {
+		if err := validateLayout(dateLayout); err != nil {
+			return Layouts{}, err
+		}
+		layouts.Date = dateLayout
+	}
+
+	return layouts, nil
+}
+
+// validateLayout rejects layouts that contain no time.Format elements,
+// which would print the layout itself instead of a time.
+func validateLayout(layout string) error {
+	if layoutCheckTime.Format(layout) == layout {
+		return fmt.Errorf("invalid layout: %q (use Go reference time elements, e.g. \"3:04PM\" or \"2006-01-02\")", layout)
+	}
+	return nil
+}
+
+// orDefault fills empty layouts from DefaultLayouts.
+func (l Layouts) orDefault() Layouts {
+	if l.Full == "" {
+		l.Full = DefaultLayouts.Full
+	}
+	if l.Short == "" {
+		l.Short = DefaultLayouts.Short
+	}
+	if l.Date == "" {
+		l.Date = DefaultLayouts.Date
+	}
+	return l
+}
@@ -0,0 +1,89 @@
+package clock
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLayouts(t *testing.T) {
+	tests := []struct {
+		name       string
+		twelveHour bool
+		timeLayout string
+		dateLayout string
+		want       Layouts
+		wantErr    bool
+	}{
+		{"defaults", false, "", "", DefaultLayouts, false},
+		{"12-hour", true, "", "", Layouts12Hour, false},
+		{"custom time", false, "Mon 3:04PM", "", Layouts{Full: "Mon 3:04PM", Short: "Mon 3:04PM", Date: LayoutDate}, false},
+		{"custom time wins over 12-hour", true, "15h04", "", Layouts{Full: "15h04", Short: "15h04", Date: LayoutDate}, false},
+		{"ISO date", false, "", "2006-01-02", Layouts{Full: LayoutFull, Short: LayoutShort, Date: "2006-01-02"}, false},
+		{"12-hour with ISO date", true, "", "2006-01-02", Layouts{Full: "3:04:05 PM", Short: "3:04 PM", Date: "2006-01-02"}, false},
+		{"invalid time layout", false, "hh:mm", "", Layouts{}, true},
+		{"invalid date layout", false, "", "yyyy-mm-dd", Layouts{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewLayouts(tt.twelveHour, tt.timeLayout, tt.dateLayout)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLayouts_OrDefault(t *testing.T) {
+	assert.Equal(t, DefaultLayouts, Layouts{}.orDefault())
+	assert.Equal(t, Layouts{Full: LayoutFull, Short: LayoutShort, Date: "2006-01-02"}, Layouts{Date: "2006-01-02"}.orDefault())
+}
+
+func TestFormatResult_Layouts(t *testing.T) {
+	now := time.Date(2024, 1, 15, 23, 6, 21, 0, time.UTC)
+	result := LookupTime("SFO", &now)
+
+	got := FormatResult(result, DisplayOptions{Layouts: Layouts12Hour, ShowDate: true})
+	assert.Contains(t, got, " 3:06:21 PM Mon Jan 15 ")
+
+	got = FormatResult(result, DisplayOptions{Layouts: Layouts{Date: "2006-01-02"}, ShowDate: true})
+	assert.Contains(t, got, " 15:06:21 2024-01-15 ")
+
+	got = FormatResult(result, DisplayOptions{Layouts: Layouts12Hour, PS1Format: true})
+	assert.Equal(t, "SFO 3:06 PM", got)
+}
+
+func TestFormatConversion_Layouts(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowConversion(&buf, TimeSpec{IATA: "SFO", Hour: 9}, []string{"JFK"}, DisplayOptions{Layouts: Layouts12Hour}, &now)
+	assert.Contains(t, buf.String(), "SFO: 🕘 9:00 AM  →  JFK: 🕛 12:00 PM (+3h)")
+
+	buf.Reset()
+	ShowConversion(&buf, TimeSpec{IATA: "SFO", Hour: 9}, []string{"JFK"}, DisplayOptions{Layouts: Layouts12Hour, PS1Format: true}, &now)
+	assert.Equal(t, "SFO 9:00 AM JFK 12:00 PM", buf.String())
+}
+
+func TestFormatOverlap_Layouts(t *testing.T) {
+	ref := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	result, err := FindOverlap([]string{"SFO", "JFK"}, DefaultWorkHours, ref)
+	require.NoError(t, err)
+
+	assert.Contains(t, FormatOverlap(result, Layouts12Hour), "9:00 AM-2:00 PM SFO = 12:00 PM-5:00 PM JFK")
+	assert.Equal(t, FormatOverlap(result, DefaultLayouts), FormatOverlap(result, Layouts{}))
+
+	// Weekdays are those of the overlap in each location, not a fixed date
+	weekday := Layouts{Short: "Mon 3:04PM"}
+	assert.Contains(t, FormatOverlap(result, weekday), "Mon 9:00AM-Mon 2:00PM SFO = Mon 12:00PM-Mon 5:00PM JFK")
+	result, err = FindOverlap([]string{"SFO", "NRT"}, DefaultWorkHours, ref)
+	require.NoError(t, err)
+	assert.Contains(t, FormatOverlap(result, weekday), "Sun 4:00PM-Sun 5:00PM SFO = Mon 9:00AM-Mon 10:00AM NRT")
+}
@@ -0,0 +1,219 @@
+package clock
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Locale holds the weekday and month names used to render dates in a language.
+// Go's time.Format only produces English names.
+type Locale struct {
+	// Days and ShortDays are indexed by time.Weekday, starting with Sunday
+	Days      [7]string
+	ShortDays [7]string
+	// Months and ShortMonths are indexed by time.Month - 1
+	Months      [12]string
+	ShortMonths [12]string
+	// DateLayout is the locale's usual order for LayoutDate's elements,
+	// used unless a date layout is given
+	DateLayout string
+	// Today, Tomorrow and Yesterday label days relative to a reference date;
+	// DayCount is a format for other differences, given a signed %+d
+	Today, Tomorrow, Yesterday string
+	DayCount                   string
+}
+
+// locales are the built-in locales, keyed by ISO 639-1 language code.
+var locales = map[string]*Locale{
+	"de": {
+		Days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		ShortDays:   [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		Months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		ShortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		DateLayout:  "Mon 2. Jan",
+		Today:       "heute",
+		Tomorrow:    "morgen",
+		Yesterday:   "gestern",
+		DayCount:    "%+d Tage",
+	},
+	"en": {
+		Days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		ShortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		Months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		ShortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		DateLayout:  LayoutDate,
+		Today:       "today",
+		Tomorrow:    "tomorrow",
+		Yesterday:   "yesterday",
+		DayCount:    "%+d days",
+	},
+	"es": {
+		Days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		ShortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		Months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		ShortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic"},
+		DateLayout:  "Mon 2 Jan",
+		Today:       "hoy",
+		Tomorrow:    "mañana",
+		Yesterday:   "ayer",
+		DayCount:    "%+d días",
+	},
+	"fr": {
+		Days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		ShortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		Months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		ShortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		DateLayout:  "Mon 2 Jan",
+		Today:       "aujourd'hui",
+		Tomorrow:    "demain",
+		Yesterday:   "hier",
+		DayCount:    "%+d jours",
+	},
+	"it": {
+		Days:        [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		ShortDays:   [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		Months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		ShortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		DateLayout:  "Mon 2 Jan",
+		Today:       "oggi",
+		Tomorrow:    "domani",
+		Yesterday:   "ieri",
+		DayCount:    "%+d giorni",
+	},
+	"ja": {
+		Days:        [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		ShortDays:   [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		Months:      [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		ShortMonths: [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		DateLayout:  "1月2日(Mon)",
+		Today:       "今日",
+		Tomorrow:    "明日",
+		Yesterday:   "昨日",
+		DayCount:    "%+d日",
+	},
+	"ko": {
+		Days:        [7]string{"일요일", "월요일", "화요일", "수요일", "목요일", "금요일", "토요일"},
+		ShortDays:   [7]string{"일", "월", "화", "수", "목", "금", "토"},
+		Months:      [12]string{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"},
+		ShortMonths: [12]string{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"},
+		DateLayout:  "1월 2일 (Mon)",
+		Today:       "오늘",
+		Tomorrow:    "내일",
+		Yesterday:   "어제",
+		DayCount:    "%+d일",
+	},
+	"nl": {
+		Days:        [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		ShortDays:   [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+		Months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		ShortMonths: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		DateLayout:  "Mon 2 Jan",
+		Today:       "vandaag",
+		Tomorrow:    "morgen",
+		Yesterday:   "gisteren",
+		DayCount:    "%+d dagen",
+	},
+	"pt": {
+		Days:        [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		ShortDays:   [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+		Months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		ShortMonths: [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		DateLayout:  "Mon 2 Jan",
+		Today:       "hoje",
+		Tomorrow:    "amanhã",
+		Yesterday:   "ontem",
+		DayCount:    "%+d dias",
+	},
+	"zh": {
+		Days:        [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+		ShortDays:   [7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
+		Months:      [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+		ShortMonths: [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		DateLayout:  "1月2日 Mon",
+		Today:       "今天",
+		Tomorrow:    "明天",
+		Yesterday:   "昨天",
+		DayCount:    "%+d天",
+	},
+}
+
+// nameTokens are the time.Format layout elements a Locale translates,
+// longest first so "Monday" isn't read as "Mon" followed by "day".
+var nameTokens = []string{"Monday", "January", "Mon", "Jan"}
+
+// LookupLocale returns the built-in locale for a language. Region and
+// encoding suffixes are ignored, so "pt-BR" and "pt_BR.UTF-8" both select "pt".
+func LookupLocale(name string) (*Locale, error) {
+	lang := strings.ToLower(name)
+	if i := strings.IndexAny(lang, "-_."); i >= 0 {
+		lang = lang[:i]
+	}
+
+	if loc, ok := locales[lang]; ok {
+		return loc, nil
+	}
+	return nil, fmt.Errorf("unsupported locale: %s (use one of %s)", name, strings.Join(LocaleNames(), ", "))
+}
+
+// LocaleNames returns the language codes of the built-in locales, sorted.
+func LocaleNames() []string {
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Format formats t like t.Format(layout), with weekday and month names in
+// the locale's language. A nil Locale formats in English.
+func (l *Locale) Format(t time.Time, layout string) string {
+	if l == nil {
+		return t.Format(layout)
+	}
+
+	var sb strings.Builder
+	for layout != "" {
+		i, token := nextNameToken(layout)
+		if i < 0 {
+			sb.WriteString(t.Format(layout))
+			break
+		}
+
+		if i > 0 {
+			sb.WriteString(t.Format(layout[:i]))
+		}
+		sb.WriteString(l.name(t, token))
+		layout = layout[i+len(token):]
+	}
+	return sb.String()
+}
+
+// nextNameToken finds the first translatable element in layout. It returns
+// -1 if there is none.
+func nextNameToken(layout string) (int, string) {
+	for i := range layout {
+		for _, token := range nameTokens {
+			if strings.HasPrefix(layout[i:], token) {
+				return i, token
+			}
+		}
+	}
+	return -1, ""
+}
+
+// name returns the localized name for a layout element.
+func (l *Locale) name(t time.Time, token string) string {
+	switch token {
+	case "Monday":
+		return l.Days[t.Weekday()]
+	case "Mon":
+		return l.ShortDays[t.Weekday()]
+	case "January":
+		return l.Months[t.Month()-1]
+	default:
+		return l.ShortMonths[t.Month()-1]
+	}
+}
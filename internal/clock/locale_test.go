@@ -0,0 +1,97 @@
+package clock
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"ja", "ja", false},
+		{"PT", "pt", false},
+		{"pt-BR", "pt", false},
+		{"de_DE.UTF-8", "de", false},
+		{"xx", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LookupLocale(tt.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Same(t, locales[tt.want], got)
+		})
+	}
+}
+
+func TestLocale_Format(t *testing.T) {
+	// Monday, 15 January 2024
+	ts := time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale string
+		layout string
+		want   string
+	}{
+		{"de", LayoutDate, "Mo Jan 15"},
+		{"de", "Monday, 2. January 2006", "Montag, 15. Januar 2024"},
+		{"fr", "Mon 2 Jan", "lun. 15 janv."},
+		{"pt", "Monday", "segunda-feira"},
+		{"ja", "1月2日(Mon)", "1月15日(月)"},
+		{"zh", "January Monday", "一月 星期一"},
+		{"es", "15:04 Mon", "09:30 lun"},
+		{"en", LayoutDate, "Mon Jan 15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+" "+tt.layout, func(t *testing.T) {
+			loc, err := LookupLocale(tt.locale)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, loc.Format(ts, tt.layout))
+		})
+	}
+}
+
+func TestLocale_FormatNil(t *testing.T) {
+	ts := time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)
+	var loc *Locale
+	assert.Equal(t, ts.Format(LayoutDate), loc.Format(ts, LayoutDate))
+}
+
+func TestLocale_DateLayouts(t *testing.T) {
+	for _, name := range LocaleNames() {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewLayouts(false, "", locales[name].DateLayout)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFormatResult_Locale(t *testing.T) {
+	now := time.Date(2024, 1, 15, 23, 6, 21, 0, time.UTC)
+	result := LookupTime("SFO", &now)
+
+	got := FormatResult(result, DisplayOptions{Locale: locales["ja"], Layouts: Layouts{Date: locales["ja"].DateLayout}, ShowDate: true})
+	assert.Contains(t, got, " 15:06:21 1月15日(月) ")
+}
+
+func TestFormatConversion_Locale(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowConversion(&buf, TimeSpec{IATA: "SFO", Hour: 20}, []string{"NRT"}, DisplayOptions{Locale: locales["fr"]}, &now)
+	assert.Contains(t, buf.String(), "20:00 lun. janv. 15")
+	assert.Contains(t, buf.String(), "13:00 mar. janv. 16")
+}
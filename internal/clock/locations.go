@@ -0,0 +1,65 @@
+package clock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cv/t/codes"
+	"github.com/cv/t/internal/tzdata"
+)
+
+// userLocations are user-defined codes, consulted before codes.IATA.
+var userLocations map[string]string
+
+// SetUserLocations installs user-defined codes (see config.LoadLocations),
+// which add to or override the generated IATA codes. Codes must be uppercase.
+// Passing nil removes them.
+func SetUserLocations(locations map[string]string) {
+	userLocations = locations
+}
+
+// LocationName returns the IANA zone name for a code, looking first at
+// user-defined codes and then at codes.IATA. The code is case-insensitive.
+func LocationName(code string) (string, bool) {
+	code = strings.ToUpper(code)
+	if locName, ok := userLocations[code]; ok {
+		return locName, true
+	}
+	locName, ok := codes.IATA[code]
+	return locName, ok
+}
+
+// CodeLocation loads the time zone of a code, e.g. to use as
+// DisplayOptions.From.
+func CodeLocation(code string) (*time.Location, error) {
+	locName, found := LocationName(code)
+	if !found {
+		return nil, fmt.Errorf("unknown IATA code: %s", strings.ToUpper(code))
+	}
+
+	loc, err := tzdata.LoadLocation(locName)
+	if err != nil {
+		return nil, fmt.Errorf("loading location %s: %w", locName, err)
+	}
+	return loc, nil
+}
+
+// isUserLocation reports whether code is a user-defined code.
+func isUserLocation(code string) bool {
+	_, ok := userLocations[strings.ToUpper(code)]
+	return ok
+}
+
+// allLocations returns every known code and its zone name, with user-defined
+// codes taking precedence.
+func allLocations() map[string]string {
+	all := make(map[string]string, len(codes.IATA)+len(userLocations))
+	for code, locName := range codes.IATA {
+		all[code] = locName
+	}
+	for code, locName := range userLocations {
+		all[code] = locName
+	}
+	return all
+}
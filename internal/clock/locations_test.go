@@ -0,0 +1,100 @@
+package clock
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/cv/t/codes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useLocations installs user-defined codes for the duration of a test.
+func useLocations(t *testing.T, locations map[string]string) {
+	t.Helper()
+	SetUserLocations(locations)
+	t.Cleanup(func() { SetUserLocations(nil) })
+}
+
+func TestLocationName(t *testing.T) {
+	useLocations(t, map[string]string{
+		"HQ":  "America/Chicago",
+		"GKA": "Australia/Brisbane",
+	})
+
+	tests := []struct {
+		code      string
+		want      string
+		wantFound bool
+	}{
+		{"HQ", "America/Chicago", true},
+		{"hq", "America/Chicago", true},
+		{"GKA", "Australia/Brisbane", true}, // user-defined codes override codes.IATA
+		{"SFO", codes.IATA["SFO"], true},
+		{"XXX", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, found := LocationName(tt.code)
+			assert.Equal(t, tt.wantFound, found)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLocationName_NoUserLocations(t *testing.T) {
+	got, found := LocationName("HQ")
+	assert.False(t, found)
+	assert.Empty(t, got)
+}
+
+func TestUserLocations_Lookups(t *testing.T) {
+	useLocations(t, map[string]string{
+		"BER1":       "Europe/Berlin",
+		"REMOTE-ANA": "America/Sao_Paulo",
+	})
+	ref := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	result := LookupTime("ber1", &ref)
+	require.True(t, result.Found)
+	assert.Equal(t, "BER1", result.IATA)
+	assert.Equal(t, "Europe/Berlin", result.Location)
+	assert.Equal(t, 14, result.Time.Hour())
+
+	spec := ParseTimeSpec("remote-ana@9")
+	require.NotNil(t, spec)
+	assert.Equal(t, "REMOTE-ANA", spec.IATA)
+	resolved, err := spec.ResolveTime(ref)
+	require.NoError(t, err)
+	assert.Equal(t, "America/Sao_Paulo", resolved.Location().String())
+
+	overlap, err := FindOverlap([]string{"BER1", "REMOTE-ANA"}, DefaultWorkHours, ref)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", overlap.Locations[0].LocName)
+
+	var buf bytes.Buffer
+	ShowConversion(&buf, *spec, []string{"BER1"}, DisplayOptions{}, &ref)
+	assert.Contains(t, buf.String(), "REMOTE-ANA: 🕘 09:00  →  BER1: 🕑 14:00")
+}
+
+func TestParseTimeSpec_UnknownLongCode(t *testing.T) {
+	useLocations(t, map[string]string{"BER1": "Europe/Berlin"})
+
+	assert.Nil(t, ParseTimeSpec("BER2@9:00"), "codes other than three letters must be user-defined")
+	assert.Nil(t, ParseTimeSpec("SFOX@9:00"))
+}
+
+func TestDiagnose_UserLocations(t *testing.T) {
+	useLocations(t, map[string]string{
+		"HQ":  "America/Chicago",
+		"BAD": "Nowhere/Town",
+	})
+
+	d := Diagnose()
+	assert.Equal(t, 2, d.UserCodes)
+	require.Len(t, d.Failures, 1)
+	assert.Equal(t, "BAD", d.Failures[0].IATA)
+	assert.Contains(t, FormatDiagnosis(d), "user-defined codes: 2 (locations.json)")
+}
@@ -6,7 +6,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/cv/t/codes"
+	"github.com/cv/t/internal/tzdata"
 )
 
 // WorkHours represents a working hours range.
@@ -15,6 +15,15 @@ type WorkHours struct {
 	End   int // End hour (0-23, exclusive)
 }
 
+// Contains reports whether hour falls within the work hours. If Start is
+// after End, the hours wrap past midnight.
+func (wh WorkHours) Contains(hour int) bool {
+	if wh.Start > wh.End {
+		return hour >= wh.Start || hour < wh.End
+	}
+	return hour >= wh.Start && hour < wh.End
+}
+
 // DefaultWorkHours is the default 9am-5pm work day.
 var DefaultWorkHours = WorkHours{Start: 9, End: 17}
 
@@ -59,6 +68,8 @@ type OverlapResult struct {
 	// OverlapHours are the overlapping hours in UTC
 	OverlapHoursUTC []int
 	WorkHours       WorkHours
+	// Date is midnight UTC on the day the overlap hours are taken from
+	Date time.Time
 }
 
 // FindOverlap finds overlapping work hours across multiple timezones.
@@ -72,12 +83,12 @@ func FindOverlap(iatas []string, workHours WorkHours, refTime time.Time) (*Overl
 
 	for _, iata := range iatas {
 		iata = strings.ToUpper(iata)
-		locName, found := codes.IATA[iata]
+		locName, found := LocationName(iata)
 		if !found {
 			return nil, fmt.Errorf("unknown IATA code: %s", iata)
 		}
 
-		loc, err := time.LoadLocation(locName)
+		loc, err := tzdata.LoadLocation(locName)
 		if err != nil {
 			return nil, fmt.Errorf("loading location %s: %w", locName, err)
 		}
@@ -117,11 +128,16 @@ func FindOverlap(iatas []string, workHours WorkHours, refTime time.Time) (*Overl
 		Locations:       locations,
 		OverlapHoursUTC: overlapHours,
 		WorkHours:       workHours,
+		Date:            refTime.UTC().Truncate(24 * time.Hour),
 	}, nil
 }
 
-// FormatOverlap formats the overlap result for display.
-func FormatOverlap(result *OverlapResult) string {
+// FormatOverlap formats the overlap result for display, showing
+// the hours in each location with layouts.Short. Layouts with a weekday or
+// date show the one each location is on during the overlap.
+func FormatOverlap(result *OverlapResult, layouts Layouts) string {
+	layouts = layouts.orDefault()
+
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("Working hours overlap (%d:00-%d:00 local):\n",
@@ -139,16 +155,11 @@ func FormatOverlap(result *OverlapResult) string {
 		sb.WriteString("  ")
 		// Show the range in each timezone
 		var parts []string
+		start := result.Date.Add(time.Duration(r.start) * time.Hour)
+		end := result.Date.Add(time.Duration(r.end) * time.Hour)
 		for _, loc := range result.Locations {
-			startLocal := (r.start + loc.Offset/3600) % 24
-			if startLocal < 0 {
-				startLocal += 24
-			}
-			endLocal := (r.end + loc.Offset/3600) % 24
-			if endLocal < 0 {
-				endLocal += 24
-			}
-			parts = append(parts, fmt.Sprintf("%02d:00-%02d:00 %s", startLocal, endLocal, loc.IATA))
+			parts = append(parts, fmt.Sprintf("%s-%s %s",
+				start.In(loc.Location).Format(layouts.Short), end.In(loc.Location).Format(layouts.Short), loc.IATA))
 		}
 		sb.WriteString(strings.Join(parts, " = "))
 		sb.WriteString("\n")
@@ -195,8 +206,9 @@ func groupConsecutiveHours(hours []int) []hourRange {
 	return ranges
 }
 
-// ShowOverlap displays overlapping work hours across timezones.
-func ShowOverlap(w io.Writer, iatas []string, workHours WorkHours, now *time.Time) {
+// ShowOverlap displays overlapping work hours across timezones,
+// formatting hours with layouts.Short.
+func ShowOverlap(w io.Writer, iatas []string, workHours WorkHours, layouts Layouts, now *time.Time) {
 	var refTime time.Time
 	if now != nil {
 		refTime = *now
@@ -210,5 +222,5 @@ func ShowOverlap(w io.Writer, iatas []string, workHours WorkHours, now *time.Tim
 		return
 	}
 
-	_, _ = fmt.Fprint(w, FormatOverlap(result))
+	_, _ = fmt.Fprint(w, FormatOverlap(result, layouts))
 }
@@ -237,6 +237,7 @@ func TestFormatOverlap(t *testing.T) {
 				},
 				OverlapHoursUTC: []int{17, 18, 19, 20, 21},
 				WorkHours:       DefaultWorkHours,
+				Date:            time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
 			},
 			wantParts: []string{
 				"Working hours overlap (9:00-17:00 local):",
@@ -253,6 +254,7 @@ func TestFormatOverlap(t *testing.T) {
 				},
 				OverlapHoursUTC: []int{},
 				WorkHours:       DefaultWorkHours,
+				Date:            time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
 			},
 			wantParts: []string{
 				"Working hours overlap (9:00-17:00 local):",
@@ -268,6 +270,7 @@ func TestFormatOverlap(t *testing.T) {
 				},
 				OverlapHoursUTC: []int{17},
 				WorkHours:       DefaultWorkHours,
+				Date:            time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
 			},
 			wantParts: []string{
 				"1 hour overlap",
@@ -277,7 +280,7 @@ func TestFormatOverlap(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := FormatOverlap(tt.result)
+			got := FormatOverlap(tt.result, DefaultLayouts)
 
 			for _, part := range tt.wantParts {
 				assert.Contains(t, got, part, "output should contain %q", part)
@@ -318,7 +321,7 @@ func TestShowOverlap(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var buf bytes.Buffer
-			ShowOverlap(&buf, tt.iatas, tt.workHours, &refTime)
+			ShowOverlap(&buf, tt.iatas, tt.workHours, DefaultLayouts, &refTime)
 			got := buf.String()
 
 			for _, part := range tt.wantParts {
@@ -330,7 +333,7 @@ func TestShowOverlap(t *testing.T) {
 
 func TestShowOverlapNilTime(t *testing.T) {
 	var buf bytes.Buffer
-	ShowOverlap(&buf, []string{"SFO", "JFK"}, DefaultWorkHours, nil)
+	ShowOverlap(&buf, []string{"SFO", "JFK"}, DefaultWorkHours, DefaultLayouts, nil)
 	got := buf.String()
 
 	// Should work with nil time (uses current time)
@@ -368,7 +371,7 @@ func TestOverlapOutputFormat(t *testing.T) {
 	refTime := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
 
 	var buf bytes.Buffer
-	ShowOverlap(&buf, []string{"SFO", "JFK"}, DefaultWorkHours, &refTime)
+	ShowOverlap(&buf, []string{"SFO", "JFK"}, DefaultWorkHours, DefaultLayouts, &refTime)
 	got := buf.String()
 
 	// Verify the output format matches expected structure
@@ -0,0 +1,214 @@
+package clock
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PromptTemplate renders one code in shell prompt output. Templates mix
+// literal text with fields in braces:
+//
+//	{code}            the code, e.g. SFO
+//	{time}            the time, with Layouts.Short or a layout: {time:15:04}
+//	{date}            the date, with Layouts.Date or a layout: {date:Jan 2}
+//	{day}             the day relative to the From location: today, tomorrow...
+//	{zone}            the zone name, e.g. America/Los_Angeles
+//	{abbr}            the zone abbreviation, e.g. PST
+//	{offset}          the offset from the From location, e.g. +3h
+//	{icon}            the icon for the time
+//	{status}          working, off hours, sleeping or weekend
+//
+// A field ending in "?" is a condition, which shows the text after it only
+// when true: {dst? ⚠} shows "⚠" if a DST transition is within the DST
+// window, and {working?}, {off?}, {sleeping?} and {weekend?} test the
+// location's Status. {unknown?} is true for codes that weren't found.
+// Write {{ and }} for literal braces.
+type PromptTemplate struct {
+	parts []promptPart
+}
+
+// promptPart is literal text, or a field with an optional argument. For
+// conditions, arg is the text shown when the condition holds.
+type promptPart struct {
+	literal string
+	field   string
+	arg     string
+	cond    bool
+}
+
+// promptFields are the fields a template may use, and whether they take
+// a layout argument.
+var promptFields = map[string]bool{
+	"code":   false,
+	"time":   true,
+	"date":   true,
+	"day":    false,
+	"zone":   false,
+	"abbr":   false,
+	"offset": false,
+	"icon":   false,
+	"status": false,
+}
+
+// promptConditions are the conditions a template may test.
+var promptConditions = map[string]bool{
+	"dst":      true,
+	"working":  true,
+	"off":      true,
+	"sleeping": true,
+	"weekend":  true,
+	"unknown":  true,
+}
+
+// ParsePromptTemplate parses a prompt template like "{code} {time:15:04}{dst? ⚠}".
+func ParsePromptTemplate(s string) (*PromptTemplate, error) {
+	var parts []promptPart
+	var literal strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "{{"), strings.HasPrefix(s[i:], "}}"):
+			literal.WriteByte(s[i])
+			i++
+		case s[i] == '}':
+			return nil, fmt.Errorf("invalid prompt template: unmatched } at %d (use }} for a literal brace)", i)
+		case s[i] == '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid prompt template: unclosed { at %d", i)
+			}
+			part, err := parsePromptField(s[i+1 : i+end])
+			if err != nil {
+				return nil, err
+			}
+			if literal.Len() > 0 {
+				parts = append(parts, promptPart{literal: literal.String()})
+				literal.Reset()
+			}
+			parts = append(parts, part)
+			i += end
+		default:
+			literal.WriteByte(s[i])
+		}
+	}
+	if literal.Len() > 0 {
+		parts = append(parts, promptPart{literal: literal.String()})
+	}
+
+	return &PromptTemplate{parts: parts}, nil
+}
+
+// parsePromptField parses the inside of a {field}, {field:arg} or
+// {condition? text}.
+func parsePromptField(s string) (promptPart, error) {
+	if name, text, ok := strings.Cut(s, "?"); ok && !strings.Contains(name, ":") {
+		name = strings.TrimSpace(name)
+		if !promptConditions[name] {
+			return promptPart{}, fmt.Errorf("invalid prompt template: unknown condition {%s?}", name)
+		}
+		return promptPart{field: name, arg: strings.TrimPrefix(text, " "), cond: true}, nil
+	}
+
+	name, arg, hasArg := strings.Cut(s, ":")
+	takesArg, ok := promptFields[name]
+	if !ok {
+		return promptPart{}, fmt.Errorf("invalid prompt template: unknown field {%s}", name)
+	}
+	if hasArg {
+		if !takesArg {
+			return promptPart{}, fmt.Errorf("invalid prompt template: {%s} takes no layout", name)
+		}
+		if err := validateLayout(arg); err != nil {
+			return promptPart{}, fmt.Errorf("invalid prompt template: {%s}: %w", s, err)
+		}
+	}
+	return promptPart{field: name, arg: arg}, nil
+}
+
+// Execute renders the template for a result.
+func (p *PromptTemplate) Execute(r TimeResult, opts DisplayOptions) string {
+	var sb strings.Builder
+	for _, part := range p.parts {
+		switch {
+		case part.field == "":
+			sb.WriteString(part.literal)
+		case part.cond:
+			if promptCondition(part.field, r, opts) {
+				sb.WriteString(part.arg)
+			}
+		default:
+			sb.WriteString(promptField(part, r, opts))
+		}
+	}
+	return sb.String()
+}
+
+// promptField renders a field for a result. Fields other than {code} are
+// "??" for codes that weren't found.
+func promptField(part promptPart, r TimeResult, opts DisplayOptions) string {
+	if part.field == "code" {
+		return r.IATA
+	}
+	if !r.Found {
+		return "??"
+	}
+
+	layouts := opts.Layouts.orDefault()
+	switch part.field {
+	case "time":
+		if part.arg != "" {
+			return opts.Locale.Format(r.Time, part.arg)
+		}
+		return opts.Locale.Format(r.Time, layouts.Short)
+	case "date":
+		if part.arg != "" {
+			return opts.Locale.Format(r.Time, part.arg)
+		}
+		return opts.Locale.Format(r.Time, layouts.Date)
+	case "day":
+		return opts.Locale.DayLabel(DayOffset(r.Time, opts.from()))
+	case "zone":
+		return r.Location
+	case "abbr":
+		return r.Time.Format("MST")
+	case "offset":
+		return strings.Trim(RelativeOffsetFrom(r.Time, opts.from()), "()")
+	case "icon":
+		return opts.Icons.Icon(r.Time)
+	case "status":
+		return LocationStatus(r.Time, opts.workHours()).String()
+	}
+	return ""
+}
+
+// promptCondition evaluates a condition for a result. Only {unknown?}
+// holds for codes that weren't found.
+func promptCondition(name string, r TimeResult, opts DisplayOptions) bool {
+	if name == "unknown" {
+		return !r.Found
+	}
+	if !r.Found {
+		return false
+	}
+
+	if name == "dst" {
+		window := opts.DSTWindow
+		if window == 0 {
+			window = DefaultDSTWindow
+		}
+		return FindDSTTransition(r.Time, window) != nil
+	}
+
+	status := LocationStatus(r.Time, opts.workHours())
+	switch name {
+	case "working":
+		return status == StatusWorking
+	case "off":
+		return status != StatusWorking
+	case "sleeping":
+		return status == StatusSleeping
+	case "weekend":
+		return status == StatusWeekend
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package clock
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePromptTemplate(t *testing.T) {
+	valid := []string{
+		"",
+		"{code}",
+		"{code} {time:15:04}{dst? ⚠}",
+		"{{{code}}}",
+		"{time:3:04PM} {date:Jan 2} {day} {zone} {abbr} {offset} {icon} {status}",
+		"{off? zz}{working?}{sleeping? z}{weekend? W}{unknown? ?}",
+		"{dst? DST: soon}",
+	}
+	for _, s := range valid {
+		t.Run(s, func(t *testing.T) {
+			_, err := ParsePromptTemplate(s)
+			assert.NoError(t, err)
+		})
+	}
+
+	invalid := []string{
+		"{code",
+		"code}",
+		"{name}",
+		"{code:15:04}",
+		"{time:hh:mm}",
+		"{lunch? yum}",
+	}
+	for _, s := range invalid {
+		t.Run(s, func(t *testing.T) {
+			_, err := ParsePromptTemplate(s)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPromptTemplate_Execute(t *testing.T) {
+	// Monday 2024-03-04 18:00 UTC: 10:00 in SFO, six days before US DST
+	now := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+	sfo := LookupTime("SFO", &now)
+	nrt := LookupTime("NRT", &now)
+	unknown := LookupTime("XYZ", &now)
+	opts := DisplayOptions{From: sfo.Time.Location(), DSTWindow: 7}
+
+	tests := []struct {
+		template string
+		r        TimeResult
+		want     string
+	}{
+		{"{code} {time}", sfo, "SFO 10:00"},
+		{"{code} {time:3:04PM}", sfo, "SFO 10:00AM"},
+		{"{date} {day}", nrt, "Tue Mar 5 tomorrow"},
+		{"{date:2006-01-02}", sfo, "2024-03-04"},
+		{"{zone} {abbr} {offset}", nrt, "Asia/Tokyo JST +17h"},
+		{"{icon} {status}", sfo, "🕙 working"},
+		{"{code}{dst? ⚠}", sfo, "SFO⚠"},
+		{"{code}{dst? ⚠}", nrt, "NRT"},
+		{"{code}{off? zz}{sleeping? (asleep)}", nrt, "NRTzz(asleep)"},
+		{"{code}{working? *}", sfo, "SFO*"},
+		{"{code} {time}{unknown? ?}", unknown, "XYZ ???"},
+		{"{code}{off? zz}", unknown, "XYZ"},
+		{"{{{code}}}", sfo, "{SFO}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			tmpl, err := ParsePromptTemplate(tt.template)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tmpl.Execute(tt.r, opts))
+		})
+	}
+}
+
+func TestShowAll_Prompt(t *testing.T) {
+	now := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+	tmpl, err := ParsePromptTemplate("{code} {time}{off? zz}")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	ShowAll(&buf, []string{"SFO", "NRT"}, DisplayOptions{PS1Format: true, Prompt: tmpl, PromptSeparator: " | "}, &now)
+	assert.Equal(t, "SFO 10:00 | NRT 03:00zz", buf.String())
+
+	// Without PS1Format, the template isn't used
+	buf.Reset()
+	ShowAll(&buf, []string{"SFO"}, DisplayOptions{Prompt: tmpl}, &now)
+	assert.Contains(t, buf.String(), "SFO: ")
+
+	buf.Reset()
+	ShowAll(&buf, []string{"SFO", "NRT"}, DisplayOptions{PS1Format: true, PromptSeparator: " · "}, &now)
+	assert.Equal(t, "SFO 10:00 · NRT 03:00", buf.String())
+}
+
+func TestFormatConversion_Prompt(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	tmpl, err := ParsePromptTemplate("{code} {time} {offset} {day}")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	ShowConversion(&buf, TimeSpec{IATA: "SFO", Hour: 20}, []string{"NRT"}, DisplayOptions{PS1Format: true, Prompt: tmpl, PromptSeparator: ", "}, &now)
+	assert.Equal(t, "SFO 20:00 +0h today, NRT 13:00 +17h tomorrow", buf.String())
+}
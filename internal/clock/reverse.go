@@ -0,0 +1,124 @@
+package clock
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cv/t/codes"
+	"github.com/cv/t/internal/tzdata"
+)
+
+// CodeInfo describes a code found by a reverse lookup.
+type CodeInfo struct {
+	IATA     string
+	Location string
+	// Name and City are empty for user-defined codes and codes without airport data
+	Name string
+	City string
+	// Country is an ISO 3166 country code, or "" if unknown
+	Country string
+}
+
+// codeInfo describes a code mapped to the zone locName. The country comes
+// from the airport data, or from the zone's zone.tab entry if that has none.
+func codeInfo(iata, locName string) CodeInfo {
+	info := CodeInfo{IATA: iata, Location: locName}
+	if airport, ok := codes.Airports[iata]; ok && !isUserLocation(iata) {
+		info.Name = airport.Name
+		info.City = airport.City
+		info.Country = airport.Country
+	}
+	if info.Country == "" {
+		info.Country, _ = tzdata.Country(locName)
+	}
+	return info
+}
+
+// FindByZone returns the codes mapped to a zone, sorted by code. Zone names
+// are matched case-insensitively, and deprecated names count as their
+// current ones, so Asia/Kolkata also finds codes mapped to Asia/Calcutta.
+func FindByZone(zone string) []CodeInfo {
+	want := tzdata.Canonical(zone)
+	return findCodes(func(info CodeInfo) bool {
+		return strings.EqualFold(tzdata.Canonical(info.Location), want)
+	})
+}
+
+// FindByCountry returns the codes in a country, given as an ISO 3166 code
+// like "IN", sorted by code.
+func FindByCountry(country string) []CodeInfo {
+	return findCodes(func(info CodeInfo) bool {
+		return strings.EqualFold(info.Country, country)
+	})
+}
+
+// FindByPrefix returns the codes starting with prefix, case-insensitively,
+// sorted by code.
+func FindByPrefix(prefix string) []CodeInfo {
+	prefix = strings.ToUpper(prefix)
+	return findCodes(func(info CodeInfo) bool {
+		return strings.HasPrefix(info.IATA, prefix)
+	})
+}
+
+// findCodes returns the known codes matching a predicate, sorted by code.
+func findCodes(match func(CodeInfo) bool) []CodeInfo {
+	var found []CodeInfo
+	for iata, locName := range allLocations() {
+		if info := codeInfo(iata, locName); match(info) {
+			found = append(found, info)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].IATA < found[j].IATA
+	})
+	return found
+}
+
+// FormatCodeList formats the result of a reverse lookup under a title such as
+// "Asia/Kolkata", one code per line with its name, city and zone.
+func FormatCodeList(title string, infos []CodeInfo) string {
+	if len(infos) == 0 {
+		return fmt.Sprintf("%s: no codes found\n", title)
+	}
+
+	var sb strings.Builder
+	noun := "codes"
+	if len(infos) == 1 {
+		noun = "code"
+	}
+	sb.WriteString(fmt.Sprintf("%s: %d %s\n", title, len(infos), noun))
+
+	for _, info := range infos {
+		var place []string
+		for _, s := range []string{info.Name, info.City} {
+			if s != "" {
+				place = append(place, s)
+			}
+		}
+		if len(place) > 0 {
+			sb.WriteString(fmt.Sprintf("  %s  %s (%s)\n", info.IATA, strings.Join(place, ", "), info.Location))
+		} else {
+			sb.WriteString(fmt.Sprintf("  %s  (%s)\n", info.IATA, info.Location))
+		}
+	}
+
+	return sb.String()
+}
+
+// ShowZone writes the codes mapped to a zone.
+func ShowZone(w io.Writer, zone string) {
+	_, _ = fmt.Fprint(w, FormatCodeList(zone, FindByZone(zone)))
+}
+
+// ShowCountry writes the codes in a country, given as an ISO 3166 code.
+func ShowCountry(w io.Writer, country string) {
+	country = strings.ToUpper(country)
+	title := country
+	if name, ok := tzdata.CountryName(country); ok {
+		title = fmt.Sprintf("%s (%s)", country, name)
+	}
+	_, _ = fmt.Fprint(w, FormatCodeList(title, FindByCountry(country)))
+}
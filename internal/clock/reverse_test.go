@@ -0,0 +1,118 @@
+package clock
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cv/t/codes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// iatasOf returns the codes of a reverse lookup result.
+func iatasOf(infos []CodeInfo) []string {
+	iatas := make([]string, len(infos))
+	for i, info := range infos {
+		iatas[i] = info.IATA
+	}
+	return iatas
+}
+
+func TestFindByZone(t *testing.T) {
+	got := FindByZone("Atlantic/Stanley")
+	assert.Equal(t, []string{"MPN", "PSY"}, iatasOf(got))
+	assert.Equal(t, "FK", got[0].Country)
+}
+
+func TestFindByZone_DeprecatedAndCase(t *testing.T) {
+	require.Equal(t, "Asia/Calcutta", codes.IATA["BOM"])
+
+	assert.Contains(t, iatasOf(FindByZone("Asia/Kolkata")), "BOM", "current names find codes mapped to deprecated ones")
+	assert.Contains(t, iatasOf(FindByZone("Asia/Calcutta")), "BOM")
+	assert.Contains(t, iatasOf(FindByZone("asia/kolkata")), "BOM")
+	assert.Empty(t, FindByZone("Nowhere/Town"))
+}
+
+func TestFindByZone_OtherCountry(t *testing.T) {
+	require.Equal(t, "America/Coral_Harbour", codes.IATA["YZS"])
+
+	panama := iatasOf(FindByZone("America/Panama"))
+	for _, code := range []string{"YIB", "YPL", "YZS"} {
+		assert.NotContains(t, panama, code, "Coral Harbour is in Canada, not Panama")
+	}
+	assert.Contains(t, iatasOf(FindByZone("America/Atikokan")), "YZS")
+}
+
+func TestFindByZone_UserLocations(t *testing.T) {
+	useLocations(t, map[string]string{"STANLEY-HQ": "Atlantic/Stanley"})
+
+	assert.Equal(t, []string{"MPN", "PSY", "STANLEY-HQ"}, iatasOf(FindByZone("Atlantic/Stanley")))
+}
+
+func TestFindByCountry(t *testing.T) {
+	assert.Equal(t, []string{"MPN", "PSY"}, iatasOf(FindByCountry("FK")))
+	assert.Equal(t, []string{"MPN", "PSY"}, iatasOf(FindByCountry("fk")))
+	assert.Contains(t, iatasOf(FindByCountry("IN")), "BOM")
+	assert.Equal(t, []string{"KSA", "PNI", "TKK", "ULI", "YAP"}, iatasOf(FindByCountry("FM")))
+	assert.Empty(t, FindByCountry("XX"))
+}
+
+func TestFindByPrefix(t *testing.T) {
+	SetUserLocations(map[string]string{"SFX-HQ": "America/Chicago"})
+	t.Cleanup(func() { SetUserLocations(nil) })
+
+	sf := iatasOf(FindByPrefix("sf"))
+	assert.Contains(t, sf, "SFO")
+	assert.Contains(t, sf, "SFX-HQ")
+	assert.IsIncreasing(t, sf)
+	for _, code := range sf {
+		assert.True(t, strings.HasPrefix(code, "SF"), code)
+	}
+	assert.Equal(t, []string{"SFO"}, iatasOf(FindByPrefix("SFO")))
+	assert.Empty(t, FindByPrefix("ZZZZ"))
+}
+
+func TestCodeInfo_AirportData(t *testing.T) {
+	orig, had := codes.Airports["PSY"]
+	codes.Airports["PSY"] = codes.Airport{Name: "Stanley Airport", City: "Stanley", Country: "FK"}
+	t.Cleanup(func() {
+		if had {
+			codes.Airports["PSY"] = orig
+		} else {
+			delete(codes.Airports, "PSY")
+		}
+	})
+
+	info := codeInfo("PSY", "Atlantic/Stanley")
+	assert.Equal(t, CodeInfo{IATA: "PSY", Location: "Atlantic/Stanley", Name: "Stanley Airport", City: "Stanley", Country: "FK"}, info)
+}
+
+func TestFormatCodeList(t *testing.T) {
+	infos := []CodeInfo{
+		{IATA: "PSY", Location: "Atlantic/Stanley", Name: "Stanley Airport", City: "Stanley"},
+		{IATA: "MPN", Location: "Atlantic/Stanley"},
+	}
+	assert.Equal(t, "Atlantic/Stanley: 2 codes\n"+
+		"  PSY  Stanley Airport, Stanley (Atlantic/Stanley)\n"+
+		"  MPN  (Atlantic/Stanley)\n", FormatCodeList("Atlantic/Stanley", infos))
+
+	assert.Equal(t, "Atlantic/Stanley: 1 code\n  MPN  (Atlantic/Stanley)\n", FormatCodeList("Atlantic/Stanley", infos[1:]))
+	assert.Equal(t, "Nowhere/Town: no codes found\n", FormatCodeList("Nowhere/Town", nil))
+}
+
+func TestShowCountry(t *testing.T) {
+	var buf bytes.Buffer
+	ShowCountry(&buf, "fk")
+	assert.Contains(t, buf.String(), "FK (Falkland Islands): 2 codes\n")
+
+	buf.Reset()
+	ShowCountry(&buf, "XX")
+	assert.Equal(t, "XX: no codes found\n", buf.String())
+}
+
+func TestShowZone(t *testing.T) {
+	var buf bytes.Buffer
+	ShowZone(&buf, "Pacific/Chatham")
+	assert.Contains(t, buf.String(), "Pacific/Chatham: 1 code\n  CHT  ")
+}
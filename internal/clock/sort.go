@@ -0,0 +1,72 @@
+package clock
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortOrder selects the order ShowAll displays codes in.
+type SortOrder int
+
+const (
+	// SortInput keeps the order the codes were given in.
+	SortInput SortOrder = iota
+	// SortName orders codes alphabetically.
+	SortName
+	// SortOffset orders codes by current UTC offset, west to east.
+	SortOffset
+	// SortEastWest orders codes by current UTC offset, east to west.
+	SortEastWest
+)
+
+// ParseSortOrder parses a --sort value: "input", "name", "offset" or "east-west".
+func ParseSortOrder(s string) (SortOrder, error) {
+	switch strings.ToLower(s) {
+	case "input":
+		return SortInput, nil
+	case "name":
+		return SortName, nil
+	case "offset", "west-east":
+		return SortOffset, nil
+	case "east-west":
+		return SortEastWest, nil
+	default:
+		return SortInput, fmt.Errorf("invalid sort order: %s (use offset, name, input or east-west)", s)
+	}
+}
+
+// SortResults sorts results in place. The sort is stable, so codes that
+// compare equal, such as two codes with the same offset, keep their input
+// order. Unknown codes are placed last.
+func SortResults(results []TimeResult, order SortOrder) {
+	if order == SortInput {
+		return
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Found != b.Found {
+			return a.Found
+		}
+		if !a.Found {
+			return false
+		}
+
+		switch order {
+		case SortName:
+			return a.IATA < b.IATA
+		case SortOffset:
+			return utcOffset(a) < utcOffset(b)
+		case SortEastWest:
+			return utcOffset(a) > utcOffset(b)
+		}
+		return false
+	})
+}
+
+// utcOffset returns a found result's UTC offset in seconds.
+func utcOffset(r TimeResult) int {
+	_, offset := r.Time.Zone()
+	return offset
+}
@@ -0,0 +1,74 @@
+package clock
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSortOrder(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    SortOrder
+		wantErr bool
+	}{
+		{"input", SortInput, false},
+		{"name", SortName, false},
+		{"offset", SortOffset, false},
+		{"OFFSET", SortOffset, false},
+		{"west-east", SortOffset, false},
+		{"east-west", SortEastWest, false},
+		{"", SortInput, true},
+		{"zone", SortInput, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSortOrder(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSortResults(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	iatas := []string{"LHR", "NRT", "XXX", "SFO", "JFK", "CDG", "EWR"}
+
+	tests := []struct {
+		order SortOrder
+		want  []string
+	}{
+		{SortInput, []string{"LHR", "NRT", "XXX", "SFO", "JFK", "CDG", "EWR"}},
+		{SortName, []string{"CDG", "EWR", "JFK", "LHR", "NRT", "SFO", "XXX"}},
+		{SortOffset, []string{"SFO", "JFK", "EWR", "LHR", "CDG", "NRT", "XXX"}},
+		{SortEastWest, []string{"NRT", "CDG", "LHR", "JFK", "EWR", "SFO", "XXX"}},
+	}
+
+	for _, tt := range tests {
+		results := lookupAll(iatas, now)
+		SortResults(results, tt.order)
+		assert.Equal(t, tt.want, groupedIATAs(results), "order %d", tt.order)
+	}
+}
+
+func TestShowAll_Sort(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowAll(&buf, []string{"NRT", "SFO", "LAX", "LHR"}, DisplayOptions{Sort: SortOffset, Group: GroupZone}, &now)
+
+	var codes []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		codes = append(codes, line[:strings.Index(line, ":")])
+	}
+	assert.Equal(t, []string{"SFO/LAX", "LHR", "NRT"}, codes)
+}
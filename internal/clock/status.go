@@ -0,0 +1,49 @@
+package clock
+
+import "time"
+
+// Status describes whether people at a location are likely reachable.
+type Status int
+
+const (
+	// StatusWorking is a weekday within work hours
+	StatusWorking Status = iota
+	// StatusOffHours is a weekday outside work and night hours
+	StatusOffHours
+	// StatusSleeping is a weekday outside work hours, from 22:00 to 6:00
+	StatusSleeping
+	// StatusWeekend is a Saturday or Sunday
+	StatusWeekend
+)
+
+// String returns the label shown for a status.
+func (s Status) String() string {
+	switch s {
+	case StatusWorking:
+		return "working"
+	case StatusOffHours:
+		return "off hours"
+	case StatusSleeping:
+		return "sleeping"
+	case StatusWeekend:
+		return "weekend"
+	default:
+		return "unknown"
+	}
+}
+
+// LocationStatus returns the status for a local time, given its work hours.
+// Weekends take precedence, then work hours, so late shifts that run into
+// the night count as working.
+func LocationStatus(t time.Time, workHours WorkHours) Status {
+	switch hour := t.Hour(); {
+	case t.Weekday() == time.Saturday || t.Weekday() == time.Sunday:
+		return StatusWeekend
+	case workHours.Contains(hour):
+		return StatusWorking
+	case nightHours.Contains(hour):
+		return StatusSleeping
+	default:
+		return StatusOffHours
+	}
+}
@@ -0,0 +1,70 @@
+package clock
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocationStatus(t *testing.T) {
+	// 2024-01-15 is a Monday
+	at := func(day, hour int) time.Time { return time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name      string
+		t         time.Time
+		workHours WorkHours
+		want      Status
+	}{
+		{"morning", at(15, 9), DefaultWorkHours, StatusWorking},
+		{"afternoon", at(15, 16), DefaultWorkHours, StatusWorking},
+		{"evening", at(15, 17), DefaultWorkHours, StatusOffHours},
+		{"early morning", at(15, 6), DefaultWorkHours, StatusOffHours},
+		{"night", at(15, 22), DefaultWorkHours, StatusSleeping},
+		{"after midnight", at(15, 3), DefaultWorkHours, StatusSleeping},
+		{"saturday", at(13, 10), DefaultWorkHours, StatusWeekend},
+		{"sunday night", at(14, 23), DefaultWorkHours, StatusWeekend},
+		{"custom hours", at(15, 19), WorkHours{Start: 12, End: 20}, StatusWorking},
+		{"late shift", at(15, 22), WorkHours{Start: 14, End: 23}, StatusWorking},
+		{"night shift", at(15, 2), WorkHours{Start: 22, End: 6}, StatusWorking},
+		{"night after a late shift", at(15, 23), WorkHours{Start: 14, End: 23}, StatusSleeping},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, LocationStatus(tt.t, tt.workHours))
+		})
+	}
+}
+
+func TestStatus_String(t *testing.T) {
+	assert.Equal(t, "working", StatusWorking.String())
+	assert.Equal(t, "off hours", StatusOffHours.String())
+	assert.Equal(t, "sleeping", StatusSleeping.String())
+	assert.Equal(t, "weekend", StatusWeekend.String())
+}
+
+func TestShowAll_Status(t *testing.T) {
+	// Monday 17:06 UTC: 09:06 in SFO, 17:06 in London, 22:36 in Bangalore
+	now := time.Date(2024, 1, 15, 17, 6, 21, 0, time.UTC)
+
+	var buf bytes.Buffer
+	ShowAll(&buf, []string{"SFO", "LHR", "BLR"}, DisplayOptions{ShowStatus: true}, &now)
+	assert.Contains(t, buf.String(), "(America/Los_Angeles) [working]\n")
+	assert.Contains(t, buf.String(), "(Europe/London) [off hours]\n")
+	assert.Contains(t, buf.String(), "[sleeping]\n")
+
+	buf.Reset()
+	ShowAll(&buf, []string{"LHR"}, DisplayOptions{ShowStatus: true, WorkHours: WorkHours{Start: 10, End: 18}}, &now)
+	assert.Contains(t, buf.String(), "(Europe/London) [working]\n")
+
+	buf.Reset()
+	ShowAll(&buf, []string{"SFO"}, DisplayOptions{}, &now)
+	assert.NotContains(t, buf.String(), "[working]")
+
+	buf.Reset()
+	ShowAll(&buf, []string{"SFO"}, DisplayOptions{ShowStatus: true, PS1Format: true}, &now)
+	assert.Equal(t, "SFO 09:06", buf.String())
+}
@@ -0,0 +1,204 @@
+package clock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// StatusLine selects a status bar's markup for ShowStatusLine.
+type StatusLine int
+
+const (
+	// StatusLineNone is ordinary output
+	StatusLineNone StatusLine = iota
+	// StatusLineTmux emits #[fg=...] styled text for status-left/right
+	StatusLineTmux
+	// StatusLineI3bar emits a JSON array of i3bar blocks, one per code
+	StatusLineI3bar
+	// StatusLineWaybar emits a JSON object for a custom module with
+	// "return-type": "json"
+	StatusLineWaybar
+	// StatusLinePolybar emits %{F...} styled text for a script module
+	StatusLinePolybar
+)
+
+// Status bar colors for times, by Status. Working times use the bar's
+// default color.
+const (
+	statusLineOffHours = "#888888"
+	statusLineNight    = "#5c5c5c"
+	statusLineUnknown  = "#cc3333"
+)
+
+// ParseStatusLine parses a --statusline value: tmux, i3bar, waybar or polybar.
+func ParseStatusLine(s string) (StatusLine, error) {
+	switch strings.ToLower(s) {
+	case "tmux":
+		return StatusLineTmux, nil
+	case "i3bar", "i3":
+		return StatusLineI3bar, nil
+	case "waybar":
+		return StatusLineWaybar, nil
+	case "polybar":
+		return StatusLinePolybar, nil
+	default:
+		return StatusLineNone, fmt.Errorf("invalid status line: %s (use tmux, i3bar, waybar or polybar)", s)
+	}
+}
+
+// statusSegment is one code's text in a status line, with its color
+// ("" for the default) and class.
+type statusSegment struct {
+	code  string
+	text  string
+	color string
+	class string
+}
+
+// ShowStatusLine writes the times for multiple IATA codes in a status bar's
+// markup. Each code is rendered like PS1Format output, with opts.Prompt if
+// set, and colored by its Status unless opts.Color is false. Waybar's
+// tooltip holds the full output with DST warnings. If now is nil, the
+// current time is used.
+func ShowStatusLine(w io.Writer, iatas []string, format StatusLine, opts DisplayOptions, now *time.Time) {
+	results := make([]TimeResult, len(iatas))
+	for i, iata := range iatas {
+		results[i] = LookupTime(iata, now)
+	}
+	results = GroupResults(results, opts.Group, opts.ShowDST, opts.DSTWindow)
+	SortResults(results, opts.Sort)
+
+	segments := make([]statusSegment, len(results))
+	for i, r := range results {
+		segments[i] = newStatusSegment(r, opts)
+	}
+
+	switch format {
+	case StatusLineTmux:
+		writeStyled(w, segments, opts.promptSeparator(), func(s statusSegment) string {
+			text := strings.ReplaceAll(s.text, "#", "##")
+			if s.color == "" {
+				return text
+			}
+			return "#[fg=" + s.color + "]" + text + "#[default]"
+		})
+	case StatusLinePolybar:
+		writeStyled(w, segments, opts.promptSeparator(), func(s statusSegment) string {
+			text := strings.ReplaceAll(s.text, "%", "%%")
+			if s.color == "" {
+				return text
+			}
+			return "%{F" + s.color + "}" + text + "%{F-}"
+		})
+	case StatusLineI3bar:
+		writeI3bar(w, segments)
+	case StatusLineWaybar:
+		writeWaybar(w, segments, iatas, opts, now)
+	}
+}
+
+// newStatusSegment renders a result for a status line.
+func newStatusSegment(r TimeResult, opts DisplayOptions) statusSegment {
+	prompt := opts
+	prompt.PS1Format = true
+	segment := statusSegment{code: r.IATA, text: FormatResult(r, prompt)}
+
+	if !r.Found {
+		if opts.Prompt == nil {
+			segment.text = r.IATA + " ??:??"
+		}
+		segment.class = "unknown"
+		if opts.Color {
+			segment.color = statusLineUnknown
+		}
+		return segment
+	}
+
+	status := LocationStatus(r.Time, opts.workHours())
+	segment.class = strings.ReplaceAll(status.String(), " ", "-")
+	if opts.Color {
+		switch status {
+		case StatusSleeping:
+			segment.color = statusLineNight
+		case StatusOffHours, StatusWeekend:
+			segment.color = statusLineOffHours
+		}
+	}
+	return segment
+}
+
+// writeStyled writes segments on one line, each marked up by style.
+func writeStyled(w io.Writer, segments []statusSegment, sep string, style func(statusSegment) string) {
+	parts := make([]string, len(segments))
+	for i, s := range segments {
+		parts[i] = style(s)
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(parts, sep))
+}
+
+// i3barBlock is a block in the i3bar protocol.
+type i3barBlock struct {
+	FullText string `json:"full_text"`
+	Name     string `json:"name"`
+	Instance string `json:"instance"`
+	Color    string `json:"color,omitempty"`
+}
+
+// writeI3bar writes segments as one line holding a JSON array of i3bar
+// blocks, to be spliced into a status command's output.
+func writeI3bar(w io.Writer, segments []statusSegment) {
+	blocks := make([]i3barBlock, len(segments))
+	for i, s := range segments {
+		blocks[i] = i3barBlock{FullText: s.text, Name: "t", Instance: s.code, Color: s.color}
+	}
+	writeJSON(w, blocks)
+}
+
+// waybarOutput is a custom module's JSON output.
+type waybarOutput struct {
+	Text    string   `json:"text"`
+	Tooltip string   `json:"tooltip"`
+	Class   []string `json:"class"`
+}
+
+// writeWaybar writes segments as a waybar custom module's JSON. Text and
+// tooltip are Pango markup; the classes are the segments' statuses, so
+// they can be styled in waybar's CSS.
+func writeWaybar(w io.Writer, segments []statusSegment, iatas []string, opts DisplayOptions, now *time.Time) {
+	out := waybarOutput{Class: []string{}}
+	parts := make([]string, len(segments))
+	for i, s := range segments {
+		parts[i] = html.EscapeString(s.text)
+		if s.color != "" {
+			parts[i] = `<span foreground="` + s.color + `">` + parts[i] + "</span>"
+		}
+		if !slices.Contains(out.Class, s.class) {
+			out.Class = append(out.Class, s.class)
+		}
+	}
+	out.Text = strings.Join(parts, html.EscapeString(opts.promptSeparator()))
+
+	full := opts
+	full.PS1Format = false
+	full.ShowDST = true
+	full.Color = false
+	full.CodeWidth = 0
+	var tooltip bytes.Buffer
+	ShowAll(&tooltip, iatas, full, now)
+	out.Tooltip = html.EscapeString(strings.TrimSuffix(tooltip.String(), "\n"))
+
+	writeJSON(w, out)
+}
+
+// writeJSON writes v as one line of JSON, leaving markup unescaped.
+func writeJSON(w io.Writer, v any) {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(v)
+}
@@ -0,0 +1,102 @@
+package clock
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatusLine(t *testing.T) {
+	tests := map[string]StatusLine{
+		"tmux":    StatusLineTmux,
+		"i3bar":   StatusLineI3bar,
+		"i3":      StatusLineI3bar,
+		"Waybar":  StatusLineWaybar,
+		"polybar": StatusLinePolybar,
+	}
+	for s, want := range tests {
+		got, err := ParseStatusLine(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+
+	for _, s := range []string{"", "dwm"} {
+		_, err := ParseStatusLine(s)
+		assert.Error(t, err, s)
+	}
+}
+
+// statusLineNow is Monday 2024-03-04 18:00 UTC: working in SFO, off hours
+// in LON and night in NRT, six days before US DST starts.
+var statusLineNow = time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+
+func showStatusLine(format StatusLine, opts DisplayOptions, iatas ...string) string {
+	var buf bytes.Buffer
+	ShowStatusLine(&buf, iatas, format, opts, &statusLineNow)
+	return buf.String()
+}
+
+func TestShowStatusLine_Tmux(t *testing.T) {
+	opts := DisplayOptions{Color: true}
+	assert.Equal(t, "SFO 10:00 #[fg=#888888]LON 18:00#[default] #[fg=#5c5c5c]NRT 03:00#[default] #[fg=#cc3333]XYZ ??:??#[default]\n",
+		showStatusLine(StatusLineTmux, opts, "sfo", "lon", "nrt", "xyz"))
+
+	opts = DisplayOptions{PromptSeparator: " | "}
+	assert.Equal(t, "SFO 10:00 | LON 18:00\n", showStatusLine(StatusLineTmux, opts, "sfo", "lon"))
+
+	// tmux formats are escaped
+	tmpl, err := ParsePromptTemplate("#{code}")
+	require.NoError(t, err)
+	opts = DisplayOptions{Prompt: tmpl}
+	assert.Equal(t, "##SFO\n", showStatusLine(StatusLineTmux, opts, "sfo"))
+}
+
+func TestShowStatusLine_Polybar(t *testing.T) {
+	tmpl, err := ParsePromptTemplate("{code} 100%")
+	require.NoError(t, err)
+	opts := DisplayOptions{Color: true, Prompt: tmpl}
+	assert.Equal(t, "SFO 100%% %{F#888888}LON 100%%%{F-}\n", showStatusLine(StatusLinePolybar, opts, "sfo", "lon"))
+}
+
+func TestShowStatusLine_I3bar(t *testing.T) {
+	opts := DisplayOptions{Color: true}
+	output := showStatusLine(StatusLineI3bar, opts, "sfo", "nrt")
+
+	var blocks []map[string]string
+	require.NoError(t, json.Unmarshal([]byte(output), &blocks))
+	assert.Equal(t, []map[string]string{
+		{"full_text": "SFO 10:00", "name": "t", "instance": "SFO"},
+		{"full_text": "NRT 03:00", "name": "t", "instance": "NRT", "color": "#5c5c5c"},
+	}, blocks)
+}
+
+func TestShowStatusLine_Waybar(t *testing.T) {
+	opts := DisplayOptions{Color: true, From: time.UTC, DSTWindow: 7}
+	output := showStatusLine(StatusLineWaybar, opts, "sfo", "lon", "nrt")
+
+	var got struct {
+		Text    string   `json:"text"`
+		Tooltip string   `json:"tooltip"`
+		Class   []string `json:"class"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &got))
+	assert.Equal(t, `SFO 10:00 <span foreground="#888888">LON 18:00</span> <span foreground="#5c5c5c">NRT 03:00</span>`, got.Text)
+	assert.Equal(t, []string{"working", "off-hours", "sleeping"}, got.Class)
+
+	// The tooltip is the full output, with DST warnings and dates
+	assert.Contains(t, got.Tooltip, "SFO: 🕙 10:00:00 Mon Mar 4 (-8h) (America/Los_Angeles) ⚠️ DST starts in 6 days (+1h)\n")
+	assert.Contains(t, got.Tooltip, "NRT: 🕒 03:00:00 Tue Mar 5 (+9h) (Asia/Tokyo)")
+	assert.NotContains(t, got.Tooltip, "\x1b[")
+
+	// Markup in text is escaped
+	tmpl, err := ParsePromptTemplate("<{code}>")
+	require.NoError(t, err)
+	opts = DisplayOptions{Prompt: tmpl}
+	output = showStatusLine(StatusLineWaybar, opts, "sfo")
+	require.NoError(t, json.Unmarshal([]byte(output), &got))
+	assert.Equal(t, "&lt;SFO&gt;", got.Text)
+}
@@ -1,5 +1,5 @@
 // Package config provides configuration management for the t CLI,
-// including alias storage and retrieval.
+// including alias storage, user-defined locations and settings.
 package config
 
 import (
@@ -52,6 +52,16 @@ func NewAliasStoreWithPath(path string) (*AliasStore, error) {
 	return store, nil
 }
 
+// NewAliasStoreFromMap creates an AliasStore holding the given aliases
+// without reading a file, e.g. from a cache. It can't be saved.
+func NewAliasStoreFromMap(aliases map[string][]string) *AliasStore {
+	store := &AliasStore{aliases: make(map[string][]string, len(aliases))}
+	for name, codes := range aliases {
+		store.aliases[strings.ToLower(name)] = codes
+	}
+	return store
+}
+
 // load reads aliases from the JSON file.
 func (s *AliasStore) load() error {
 	data, err := os.ReadFile(s.path)
@@ -68,6 +78,10 @@ func (s *AliasStore) load() error {
 
 // save writes aliases to the JSON file.
 func (s *AliasStore) save() error {
+	if s.path == "" {
+		return errors.New("alias store has no file to save to")
+	}
+
 	// Ensure directory exists
 	dir := filepath.Dir(s.path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
@@ -225,3 +225,11 @@ func TestAliasStore_LoadCorruptFile(t *testing.T) {
 	_, err = NewAliasStoreWithPath(path)
 	assert.Error(t, err)
 }
+
+func TestNewAliasStoreFromMap(t *testing.T) {
+	store := NewAliasStoreFromMap(map[string][]string{"Team": {"SFO", "LON"}})
+
+	assert.Equal(t, []string{"SFO", "LON"}, store.Get("team"))
+	assert.True(t, store.Exists("TEAM"))
+	assert.Error(t, store.Save("other", []string{"NRT"}), "an in-memory store has no file")
+}
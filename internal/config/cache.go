@@ -0,0 +1,151 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultCacheTTL is how long a prompt cache is used before it is rebuilt.
+const DefaultCacheTTL = time.Hour
+
+// PromptCache is a snapshot of what a run reads from the config directory
+// and zoneinfo, so prompt hooks can start from one small file instead.
+type PromptCache struct {
+	Created   time.Time           `json:"created"`
+	Settings  Settings            `json:"settings"`
+	Locations map[string]string   `json:"locations,omitempty"`
+	Aliases   map[string][]string `json:"aliases,omitempty"`
+	// Zones holds the tzfile data for the zones used so far, by name.
+	// It is stored raw after the JSON header, not base64 encoded in it.
+	Zones map[string][]byte `json:"-"`
+}
+
+// promptCacheHeader is the JSON line a cache file starts with. The zone
+// data follows it, in ZoneSizes order.
+type promptCacheHeader struct {
+	*PromptCache
+	ZoneSizes []zoneSize `json:"zones,omitempty"`
+}
+
+// zoneSize is the length of a zone's data in a cache file.
+type zoneSize struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
+
+// DefaultCachePath returns the path of the prompt cache, in the user's
+// cache directory (e.g. ~/.cache/t/prompt.cache).
+func DefaultCachePath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("getting cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, "t", "prompt.cache"), nil
+}
+
+// LoadPromptCache reads a prompt cache written by Save.
+func LoadPromptCache(path string) (*PromptCache, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading prompt cache: %w", err)
+	}
+
+	line, rest, ok := bytes.Cut(data, []byte("\n"))
+	if !ok {
+		return nil, fmt.Errorf("parsing %s: missing header", path)
+	}
+	cache := &PromptCache{}
+	header := promptCacheHeader{PromptCache: cache}
+	if err := json.Unmarshal(line, &header); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	cache.Zones = make(map[string][]byte, len(header.ZoneSizes))
+	for _, zone := range header.ZoneSizes {
+		if zone.Size < 0 || zone.Size > len(rest) {
+			return nil, fmt.Errorf("parsing %s: truncated zone %s", path, zone.Name)
+		}
+		cache.Zones[zone.Name] = rest[:zone.Size:zone.Size]
+		rest = rest[zone.Size:]
+	}
+	return cache, nil
+}
+
+// Fresh reports whether the cache is younger than its TTL at now: the
+// cached cache_ttl setting, or DefaultCacheTTL.
+func (c *PromptCache) Fresh(now time.Time) bool {
+	ttl := DefaultCacheTTL
+	if c.Settings.CacheTTL != "" {
+		parsed, err := time.ParseDuration(c.Settings.CacheTTL)
+		if err != nil {
+			return false
+		}
+		ttl = parsed
+	}
+
+	age := now.Sub(c.Created)
+	return age >= 0 && age < ttl
+}
+
+// Save writes the cache to a file: a JSON header line followed by the
+// zone data. The file is replaced atomically, so concurrent prompts never
+// read a partial cache.
+func (c *PromptCache) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	header := promptCacheHeader{PromptCache: c}
+	for name, data := range c.Zones {
+		header.ZoneSizes = append(header.ZoneSizes, zoneSize{Name: name, Size: len(data)})
+	}
+	sort.Slice(header.ZoneSizes, func(i, j int) bool {
+		return header.ZoneSizes[i].Name < header.ZoneSizes[j].Name
+	})
+
+	line, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("marshaling prompt cache: %w", err)
+	}
+	data := append(line, '\n')
+	for _, zone := range header.ZoneSizes {
+		data = append(data, c.Zones[zone.Name]...)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".prompt-*.cache")
+	if err != nil {
+		return fmt.Errorf("writing prompt cache: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing prompt cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing prompt cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing prompt cache: %w", err)
+	}
+	return nil
+}
+
+// ClearPromptCache removes the prompt cache, so the next cached run reads
+// the config directory again. A missing cache is not an error.
+func ClearPromptCache() error {
+	path, err := DefaultCachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing prompt cache: %w", err)
+	}
+	return nil
+}
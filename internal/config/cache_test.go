@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptCache_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "t", "prompt.cache")
+	created := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	cache := &PromptCache{
+		Created:   created,
+		Settings:  Settings{From: "lon", CacheTTL: "10m"},
+		Locations: map[string]string{"HQ": "America/Chicago"},
+		Aliases:   map[string][]string{"team": {"SFO", "LON"}},
+		Zones:     map[string][]byte{"Asia/Tokyo": {'T', 'Z', 'i', 'f', 0}},
+	}
+	require.NoError(t, cache.Save(path))
+
+	got, err := LoadPromptCache(path)
+	require.NoError(t, err)
+	assert.True(t, got.Created.Equal(created))
+	got.Created = created
+	assert.Equal(t, cache, got)
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestLoadPromptCache_Errors(t *testing.T) {
+	_, err := LoadPromptCache(filepath.Join(t.TempDir(), "missing.cache"))
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "prompt.cache")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	_, err = LoadPromptCache(path)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"zones": [{"name": "Asia/Tokyo", "size": 100}]}`+"\nTZif"), 0o644))
+	_, err = LoadPromptCache(path)
+	assert.ErrorContains(t, err, "truncated zone Asia/Tokyo")
+}
+
+func TestPromptCache_Fresh(t *testing.T) {
+	created := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	cache := &PromptCache{Created: created}
+	assert.True(t, cache.Fresh(created.Add(59*time.Minute)))
+	assert.False(t, cache.Fresh(created.Add(DefaultCacheTTL)))
+	assert.False(t, cache.Fresh(created.Add(-time.Minute)), "caches from the future are stale")
+
+	cache.Settings.CacheTTL = "5m"
+	assert.True(t, cache.Fresh(created.Add(4*time.Minute)))
+	assert.False(t, cache.Fresh(created.Add(6*time.Minute)))
+
+	cache.Settings.CacheTTL = "soon"
+	assert.False(t, cache.Fresh(created))
+}
+
+func TestClearPromptCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	require.NoError(t, ClearPromptCache(), "a missing cache is not an error")
+
+	path, err := DefaultCachePath()
+	require.NoError(t, err)
+	require.NoError(t, (&PromptCache{}).Save(path))
+	require.NoError(t, ClearPromptCache())
+	assert.NoFileExists(t, path)
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadLocations reads user-defined location codes from locations.json in the
+// default config directory. A missing file yields an empty map.
+func LoadLocations() (map[string]string, error) {
+	configDir, err := DefaultConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return LoadLocationsFromPath(filepath.Join(configDir, "locations.json"))
+}
+
+// LoadLocationsFromPath reads user-defined location codes from a JSON object
+// mapping codes to IANA zone names, e.g. {"HQ": "America/Chicago"}. Codes are
+// case-insensitive and returned uppercase. A missing file yields an empty map.
+func LoadLocationsFromPath(path string) (map[string]string, error) {
+	locations := make(map[string]string)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return locations, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading locations: %w", err)
+	}
+
+	if len(data) == 0 {
+		return locations, nil
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	for code, zone := range raw {
+		if !ValidLocationCode(code) {
+			return nil, fmt.Errorf("invalid location code %q in %s (use letters, digits, '-' and '_', starting with a letter)", code, path)
+		}
+		if strings.TrimSpace(zone) == "" {
+			return nil, fmt.Errorf("location code %q in %s has no time zone", code, path)
+		}
+		locations[strings.ToUpper(code)] = strings.TrimSpace(zone)
+	}
+
+	return locations, nil
+}
+
+// ValidLocationCode reports whether s can be used as a user-defined code.
+// Codes start with a letter so they can't be mistaken for flags or aliases,
+// and contain only ASCII letters, digits, '-' and '_', e.g. "BER1" or "REMOTE-ANA".
+func ValidLocationCode(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z'):
+		case i > 0 && ((c >= '0' && c <= '9') || c == '-' || c == '_'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLocations(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "locations.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadLocationsFromPath(t *testing.T) {
+	path := writeLocations(t, `{"hq": "America/Chicago", "REMOTE-ANA": " Europe/Lisbon ", "GKA": "Pacific/Port_Moresby"}`)
+
+	got, err := LoadLocationsFromPath(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"HQ":         "America/Chicago",
+		"REMOTE-ANA": "Europe/Lisbon",
+		"GKA":        "Pacific/Port_Moresby",
+	}, got)
+}
+
+func TestLoadLocationsFromPath_Missing(t *testing.T) {
+	got, err := LoadLocationsFromPath(filepath.Join(t.TempDir(), "locations.json"))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestLoadLocationsFromPath_Empty(t *testing.T) {
+	got, err := LoadLocationsFromPath(writeLocations(t, ""))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestLoadLocationsFromPath_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"not json", `{`},
+		{"not an object of strings", `{"HQ": ["America/Chicago"]}`},
+		{"bad code", `{"@HQ": "America/Chicago"}`},
+		{"empty zone", `{"HQ": ""}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadLocationsFromPath(writeLocations(t, tt.content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValidLocationCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"HQ", true},
+		{"BER1", true},
+		{"REMOTE-ANA", true},
+		{"home_office", true},
+		{"", false},
+		{"1HQ", false},
+		{"-HQ", false},
+		{"@HQ", false},
+		{"H Q", false},
+		{"HQ@9", false},
+		{"ÜBER", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidLocationCode(tt.code))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Settings are user defaults read from config.json in the config directory.
+// Command-line flags override them.
+type Settings struct {
+	// From is the code relative offsets are shown against, e.g. "LON".
+	// Empty means the local time zone.
+	From string `json:"from,omitempty"`
+	// TwelveHour shows times on a 12-hour clock, like --12h
+	TwelveHour bool `json:"12h,omitempty"`
+	// Layout is a custom time layout, like --layout
+	Layout string `json:"layout,omitempty"`
+	// DateLayout is a custom date layout, like --date-layout
+	DateLayout string `json:"date_layout,omitempty"`
+	// Locale is the language for weekday and month names, like --locale
+	Locale string `json:"locale,omitempty"`
+	// RelativeDays labels dates "today", "tomorrow" and so on, like --relative
+	RelativeDays bool `json:"relative_days,omitempty"`
+	// Color is when to color output: "auto", "always" or "never", like --color
+	Color string `json:"color,omitempty"`
+	// Status annotates rows with work status, like --status
+	Status bool `json:"status,omitempty"`
+	// WorkHours are the work hours, like --hours, e.g. "9-17"
+	WorkHours string `json:"work_hours,omitempty"`
+	// Icons is the icon set shown next to times, like --icons
+	Icons string `json:"icons,omitempty"`
+	// Prompt is the template for shell prompt output, like --prompt
+	Prompt string `json:"prompt,omitempty"`
+	// PromptSeparator goes between codes in prompt output, like --prompt-sep
+	PromptSeparator string `json:"prompt_sep,omitempty"`
+	// CacheTTL is how long --cached runs reuse the prompt cache, e.g. "10m"
+	CacheTTL string `json:"cache_ttl,omitempty"`
+}
+
+// LoadSettings reads config.json from the default config directory.
+// A missing file yields zero Settings.
+func LoadSettings() (*Settings, error) {
+	configDir, err := DefaultConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return LoadSettingsFromPath(filepath.Join(configDir, "config.json"))
+}
+
+// LoadSettingsFromPath reads settings from a JSON file. Unknown keys are an
+// error, so a typo doesn't silently leave a setting at its default.
+// A missing file yields zero Settings.
+func LoadSettingsFromPath(path string) (*Settings, error) {
+	settings := &Settings{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading settings: %w", err)
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return settings, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(settings); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	return settings, nil
+}
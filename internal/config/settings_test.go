@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSettings(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadSettingsFromPath(t *testing.T) {
+	got, err := LoadSettingsFromPath(writeSettings(t, `{"from": "lon"}`))
+	require.NoError(t, err)
+	assert.Equal(t, &Settings{From: "lon"}, got)
+}
+
+func TestLoadSettingsFromPath_Layouts(t *testing.T) {
+	got, err := LoadSettingsFromPath(writeSettings(t, `{"12h": true, "layout": "3:04PM", "date_layout": "2006-01-02"}`))
+	require.NoError(t, err)
+	assert.Equal(t, &Settings{TwelveHour: true, Layout: "3:04PM", DateLayout: "2006-01-02"}, got)
+}
+
+func TestLoadSettingsFromPath_Locale(t *testing.T) {
+	got, err := LoadSettingsFromPath(writeSettings(t, `{"locale": "ja", "relative_days": true, "color": "never", "status": true, "work_hours": "8-18", "icons": "ascii", "prompt": "{code}", "prompt_sep": "|", "cache_ttl": "10m"}`))
+	require.NoError(t, err)
+	assert.Equal(t, &Settings{Locale: "ja", RelativeDays: true, Color: "never", Status: true, WorkHours: "8-18", Icons: "ascii", Prompt: "{code}", PromptSeparator: "|", CacheTTL: "10m"}, got)
+}
+
+func TestLoadSettingsFromPath_Missing(t *testing.T) {
+	got, err := LoadSettingsFromPath(filepath.Join(t.TempDir(), "config.json"))
+	require.NoError(t, err)
+	assert.Equal(t, &Settings{}, got)
+}
+
+func TestLoadSettingsFromPath_Empty(t *testing.T) {
+	got, err := LoadSettingsFromPath(writeSettings(t, "\n"))
+	require.NoError(t, err)
+	assert.Equal(t, &Settings{}, got)
+}
+
+func TestLoadSettingsFromPath_Invalid(t *testing.T) {
+	_, err := LoadSettingsFromPath(writeSettings(t, `{"from": `))
+	assert.Error(t, err)
+
+	_, err = LoadSettingsFromPath(writeSettings(t, `{"form": "lon"}`))
+	assert.Error(t, err, "unknown keys are rejected")
+}
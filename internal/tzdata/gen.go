@@ -0,0 +1,283 @@
+//go:build ignore
+
+// This program generates links.go from a compiled tz database, mapping each
+// deprecated zone name (a link kept for backward compatibility, such as
+// Asia/Calcutta) to its current name, and countries.go, mapping zones to the
+// ISO 3166 country zone.tab assigns them and country codes to their names.
+//
+// A link is treated as deprecated when its name is not listed in zone.tab or
+// zone1970.tab, which name every zone the tz maintainers consider current.
+// Its current name is the link's target, unless that is in another country
+// than the zone.tab entry that names the link's city (see renamedZone).
+// Links to Etc zones, such as UTC and GMT, aren't: they are standard aliases,
+// not renamed zones.
+//
+// Usage: go generate ./internal/tzdata/... (reads /usr/share/zoneinfo)
+//
+//	go run gen.go -zoneinfo /path/to/zoneinfo
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	outputFile    = "links.go"
+	countriesFile = "countries.go"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gen: ")
+
+	zoneinfo := flag.String("zoneinfo", "/usr/share/zoneinfo", "compiled tz database with tzdata.zi and zone.tab")
+	flag.Parse()
+
+	version, links, err := readLinks(filepath.Join(*zoneinfo, "tzdata.zi"))
+	if err != nil {
+		log.Fatalf("Failed to read links: %v", err)
+	}
+
+	current := make(map[string]bool)
+	var zoneCountries map[string]string
+	for _, tab := range []string{"zone.tab", "zone1970.tab"} {
+		zones, err := readTab(filepath.Join(*zoneinfo, tab), 2, 0)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", tab, err)
+		}
+		for zone := range zones {
+			current[zone] = true
+		}
+		// zone.tab has one country per zone; zone1970.tab lists several
+		if tab == "zone.tab" {
+			zoneCountries = zones
+		}
+	}
+
+	countryNames, err := readTab(filepath.Join(*zoneinfo, "iso3166.tab"), 0, 1)
+	if err != nil {
+		log.Fatalf("Failed to read iso3166.tab: %v", err)
+	}
+
+	comments, err := readTab(filepath.Join(*zoneinfo, "zone.tab"), 2, 3)
+	if err != nil {
+		log.Fatalf("Failed to read zone.tab: %v", err)
+	}
+
+	deprecated := make(map[string]string)
+	for name, target := range links {
+		if current[name] || strings.HasPrefix(target, "Etc/") {
+			continue
+		}
+		if zone, ok := renamedZone(name, target, zoneCountries, comments); ok {
+			target = zone
+		}
+		deprecated[name] = target
+	}
+	log.Printf("Found %d deprecated zone names in tzdata %s", len(deprecated), version)
+
+	if err := generateCode(version, deprecated); err != nil {
+		log.Fatalf("Failed to generate code: %v", err)
+	}
+	log.Printf("Found %d zones in %d countries", len(zoneCountries), len(countryNames))
+	if err := generateCountries(version, zoneCountries, countryNames); err != nil {
+		log.Fatalf("Failed to generate code: %v", err)
+	}
+	log.Println("Done!")
+}
+
+// renamedZone finds the current zone a link was renamed to when tzdata.zi
+// links it into another country instead. tzdata.zi links zones whose clocks
+// have agreed since 1970, so Pacific/Truk (Chuuk, in Micronesia) points at
+// Pacific/Port_Moresby. The zone.tab comment of the current zone usually names
+// the old city, as in "Chuuk/Truk, Yap", so a single zone in the same region
+// whose comment names it, in another country than target, is the renamed zone.
+func renamedZone(name, target string, countries, comments map[string]string) (string, bool) {
+	region, _, _ := strings.Cut(name, "/")
+	city := name[strings.LastIndex(name, "/")+1:]
+
+	var found []string
+	for zone, comment := range comments {
+		if strings.HasPrefix(zone, region+"/") && countries[zone] != countries[target] && mentions(comment, city) {
+			found = append(found, zone)
+		}
+	}
+	if len(found) != 1 {
+		return "", false
+	}
+	return found[0], true
+}
+
+// mentions reports whether a zone.tab comment names a city such as
+// Coral_Harbour. The last word of a city of several words may be
+// abbreviated, as in "NU (Coral H)".
+func mentions(comment, city string) bool {
+	want := strings.Split(city, "_")
+	words := strings.FieldsFunc(comment, func(r rune) bool { return !unicode.IsLetter(r) })
+
+	for i := 0; i+len(want) <= len(words); i++ {
+		match := true
+		for j, w := range want {
+			got := words[i+j]
+			abbreviated := j > 0 && j == len(want)-1 && strings.HasPrefix(w, got)
+			if got != w && !abbreviated {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// readLinks parses the version and the "L target name" link lines of tzdata.zi.
+func readLinks(path string) (string, map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+
+	var version string
+	links := make(map[string]string)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 3 && fields[0] == "#" && fields[1] == "version":
+			version = fields[2]
+		case len(fields) == 3 && fields[0] == "L":
+			links[fields[2]] = fields[1]
+		}
+	}
+
+	return version, links, scanner.Err()
+}
+
+// readTab reads a tab-separated tz table such as zone.tab or iso3166.tab into
+// a map from the key column to the value column, skipping comments.
+func readTab(path string, key, value int) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	table := make(map[string]string)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if fields := strings.Split(line, "\t"); len(fields) > key && len(fields) > value {
+			table[fields[key]] = fields[value]
+		}
+	}
+
+	return table, scanner.Err()
+}
+
+// generateCode writes links.go with the given mappings.
+func generateCode(version string, deprecated map[string]string) error {
+	var buf bytes.Buffer
+
+	buf.WriteString(`// Code generated by go generate; DO NOT EDIT.
+`)
+	buf.WriteString(fmt.Sprintf("// Source: tz database %s (tzdata.zi, zone.tab, zone1970.tab)\n", version))
+	buf.WriteString(`
+package tzdata
+
+// deprecatedNames maps zone names kept only for backward compatibility to
+// their current names. For example, "Asia/Calcutta" maps to "Asia/Kolkata".
+var deprecatedNames = map[string]string{
+`)
+
+	// Sort keys for deterministic output
+	names := make([]string, 0, len(deprecated))
+	for name := range deprecated {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		buf.WriteString(fmt.Sprintf("\t%q: %q,\n", name, deprecated[name]))
+	}
+
+	buf.WriteString("}\n")
+
+	// Format the generated code
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format code: %w", err)
+	}
+
+	if err := os.WriteFile(outputFile, formatted, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+// generateCountries writes countries.go with the zone.tab countries and iso3166.tab names.
+func generateCountries(version string, zoneCountries, countryNames map[string]string) error {
+	var buf bytes.Buffer
+
+	buf.WriteString(`// Code generated by go generate; DO NOT EDIT.
+`)
+	buf.WriteString(fmt.Sprintf("// Source: tz database %s (zone.tab, iso3166.tab)\n", version))
+	buf.WriteString(`
+package tzdata
+
+// zoneCountries maps zone names to the ISO 3166 country zone.tab lists them
+// under. For example, "Asia/Kolkata" maps to "IN".
+var zoneCountries = map[string]string{
+`)
+	writeSorted(&buf, zoneCountries)
+	buf.WriteString(`}
+
+// countryNames maps ISO 3166 country codes to their names.
+// For example, "IN" maps to "India".
+var countryNames = map[string]string{
+`)
+	writeSorted(&buf, countryNames)
+	buf.WriteString("}\n")
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format code: %w", err)
+	}
+
+	if err := os.WriteFile(countriesFile, formatted, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+// writeSorted writes map entries as Go map literal lines, sorted by key.
+func writeSorted(buf *bytes.Buffer, m map[string]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		buf.WriteString(fmt.Sprintf("\t%q: %q,\n", k, m[k]))
+	}
+}
@@ -0,0 +1,146 @@
+package tzdata
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Source identifies where zone data is being loaded from.
+type Source string
+
+const (
+	// SourceSelected is a directory or zip file chosen with Use.
+	SourceSelected Source = "--tzdata"
+	// SourceEnv is the ZONEINFO environment variable.
+	SourceEnv Source = "ZONEINFO"
+	// SourceSystem is the operating system's zoneinfo directory.
+	SourceSystem Source = "system zoneinfo"
+	// SourceEmbedded is the copy compiled into the binary.
+	SourceEmbedded Source = "embedded"
+	// SourceNone means no zone data is available.
+	SourceNone Source = "none"
+)
+
+// systemDirs are the zoneinfo directories the time package searches on Unix.
+var systemDirs = []string{
+	"/usr/share/zoneinfo",
+	"/usr/share/lib/zoneinfo",
+	"/usr/lib/locale/TZ",
+	"/etc/zoneinfo",
+}
+
+// Info describes the zone data in use.
+type Info struct {
+	Source Source
+	// Path is the directory or zip file, if the source has one
+	Path string
+	// Version is the tz database release (e.g., "2024a"), or "" if unknown
+	Version string
+}
+
+// Current reports which zone data LoadLocation will use, following the same
+// search order as the time package when no source has been selected.
+func Current() Info {
+	// Hold mu while reading the selected source, so Use can't close it
+	mu.Lock()
+	if current != nil {
+		defer mu.Unlock()
+		return Info{Source: SourceSelected, Path: current.path, Version: current.version()}
+	}
+	mu.Unlock()
+
+	if env := os.Getenv("ZONEINFO"); env != "" {
+		if src, err := newSource(env); err == nil {
+			defer func() { _ = src.close() }()
+			return Info{Source: SourceEnv, Path: env, Version: src.version()}
+		}
+	}
+
+	for _, dir := range systemDirs {
+		if _, err := os.Stat(dir); err == nil {
+			src := &source{path: dir}
+			return Info{Source: SourceSystem, Path: dir, Version: src.version()}
+		}
+	}
+
+	if Embedded {
+		return Info{Source: SourceEmbedded}
+	}
+	return Info{Source: SourceNone}
+}
+
+// Deprecated reports whether name is a zone name kept only for backward
+// compatibility, and if so returns its current name.
+func Deprecated(name string) (string, bool) {
+	current, ok := deprecatedNames[name]
+	return current, ok
+}
+
+// Canonical returns the current name for a zone, resolving deprecated names
+// such as Asia/Calcutta. Other names are returned unchanged.
+func Canonical(name string) string {
+	if current, ok := deprecatedNames[name]; ok {
+		return current
+	}
+	return name
+}
+
+// Country returns the ISO 3166 country code that zone.tab lists a zone under,
+// e.g. "IN" for Asia/Kolkata or Asia/Calcutta. A name zone.tab lists is looked
+// up as is; deprecated names use their current name's country. Zones that
+// aren't tied to a country, such as Etc/UTC, return false.
+func Country(name string) (string, bool) {
+	if country, ok := zoneCountries[name]; ok {
+		return country, true
+	}
+	country, ok := zoneCountries[Canonical(name)]
+	return country, ok
+}
+
+// CountryName returns the name of an ISO 3166 country code, e.g. "India" for "IN".
+func CountryName(code string) (string, bool) {
+	name, ok := countryNames[code]
+	return name, ok
+}
+
+// version reads the tz database release from the source. Compiled databases
+// record it in tzdata.zi ("# version 2024a") or a +VERSION file.
+func (s *source) version() string {
+	if data, err := s.readHead("+VERSION"); err == nil {
+		if v := strings.TrimSpace(data); v != "" {
+			return v
+		}
+	}
+
+	if line, err := s.readHead("tzdata.zi"); err == nil {
+		if v, ok := strings.CutPrefix(line, "# version "); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+
+	return ""
+}
+
+// readHead returns the first line of a file in the source.
+func (s *source) readHead(name string) (string, error) {
+	var r io.ReadCloser
+	var err error
+	if s.zip == nil {
+		r, err = os.Open(filepath.Join(s.path, name))
+	} else {
+		r, err = s.zip.Open(name)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = r.Close() }()
+
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return line, nil
+}
@@ -0,0 +1,132 @@
+package tzdata
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrent_Selected(t *testing.T) {
+	dir := writeZoneDir(t, "Asia/Tokyo")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tzdata.zi"), []byte("# version 2024a\n# ddeps\n"), 0o644))
+	useTZData(t, dir)
+
+	info := Current()
+	assert.Equal(t, SourceSelected, info.Source)
+	assert.Equal(t, dir, info.Path)
+	assert.Equal(t, "2024a", info.Version)
+}
+
+func TestCurrent_ZipVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zoneinfo.zip")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("+VERSION")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("2023c\n"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	useTZData(t, path)
+	assert.Equal(t, "2023c", Current().Version)
+}
+
+func TestCurrent_UnknownVersion(t *testing.T) {
+	useTZData(t, writeZoneDir(t, "Asia/Tokyo"))
+	assert.Empty(t, Current().Version)
+}
+
+func TestCurrent_Env(t *testing.T) {
+	dir := writeZoneDir(t, "Asia/Tokyo")
+	t.Setenv("ZONEINFO", dir)
+
+	info := Current()
+	assert.Equal(t, SourceEnv, info.Source)
+	assert.Equal(t, dir, info.Path)
+}
+
+func TestCurrent_Default(t *testing.T) {
+	t.Setenv("ZONEINFO", "")
+
+	info := Current()
+	switch {
+	case info.Source == SourceSystem:
+		assert.NotEmpty(t, info.Path)
+	case Embedded:
+		assert.Equal(t, SourceEmbedded, info.Source)
+	default:
+		assert.Equal(t, SourceNone, info.Source)
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	current, ok := Deprecated("Asia/Calcutta")
+	assert.True(t, ok)
+	assert.Equal(t, "Asia/Kolkata", current)
+
+	current, ok = Deprecated("Europe/Kiev")
+	assert.True(t, ok)
+	assert.Equal(t, "Europe/Kyiv", current)
+
+	_, ok = Deprecated("Asia/Kolkata")
+	assert.False(t, ok)
+
+	_, ok = Deprecated("Europe/Amsterdam")
+	assert.False(t, ok, "zones listed in zone.tab are current even if they are links")
+
+	for _, name := range []string{"UTC", "GMT", "Etc/UCT", "Zulu"} {
+		_, ok = Deprecated(name)
+		assert.False(t, ok, "%s is an alias for an Etc zone, not a renamed zone", name)
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	assert.Equal(t, "Asia/Kolkata", Canonical("Asia/Calcutta"))
+	assert.Equal(t, "Asia/Kolkata", Canonical("Asia/Kolkata"))
+	assert.Equal(t, "Nowhere/Town", Canonical("Nowhere/Town"))
+	// tzdata.zi links these into another country; keep them in their own
+	assert.Equal(t, "Pacific/Chuuk", Canonical("Pacific/Truk"))
+	assert.Equal(t, "America/Atikokan", Canonical("America/Coral_Harbour"))
+}
+
+func TestCountry(t *testing.T) {
+	tests := []struct {
+		zone   string
+		want   string
+		wantOK bool
+	}{
+		{"Asia/Kolkata", "IN", true},
+		{"Asia/Calcutta", "IN", true},
+		{"Pacific/Chuuk", "FM", true},
+		{"Pacific/Truk", "FM", true},
+		{"Pacific/Ponape", "FM", true},
+		{"America/Coral_Harbour", "CA", true},
+		{"Europe/Amsterdam", "NL", true},
+		{"America/Los_Angeles", "US", true},
+		{"Etc/UTC", "", false},
+		{"Nowhere/Town", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zone, func(t *testing.T) {
+			got, ok := Country(tt.zone)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCountryName(t *testing.T) {
+	name, ok := CountryName("IN")
+	assert.True(t, ok)
+	assert.Equal(t, "India", name)
+
+	_, ok = CountryName("XX")
+	assert.False(t, ok)
+}
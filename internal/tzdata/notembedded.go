@@ -0,0 +1,7 @@
+//go:build !timetzdata
+
+package tzdata
+
+// Embedded reports whether the binary carries its own copy of the tz database.
+// Building with -tags timetzdata makes the time package embed it.
+const Embedded = false
@@ -0,0 +1,285 @@
+//go:generate go run gen.go
+
+// Package tzdata selects where the t CLI loads IANA time zone data from.
+//
+// By default locations are loaded the way the time package does it: from the
+// ZONEINFO environment variable, then the system zoneinfo directories, then the
+// copy embedded in the binary when it is built with -tags timetzdata. Use can
+// point lookups at a specific zoneinfo directory or zip file instead, so the
+// same query can be checked against a particular tz database release.
+package tzdata
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cv/t/internal/config"
+)
+
+// versionRegex matches IANA release names like "2024a".
+var versionRegex = regexp.MustCompile(`^\d{4}[a-z]$`)
+
+// source is a user-selected zoneinfo directory or zip file.
+type source struct {
+	path string
+	zip  *zip.ReadCloser // nil for a directory
+}
+
+var (
+	mu      sync.Mutex
+	current *source // nil means the time package's default lookup
+	cache   = make(map[string]*time.Location)
+	// preloaded holds tzfile data by name, parsed when first loaded
+	preloaded = make(map[string][]byte)
+)
+
+// Use selects the zone data source from a --tzdata value. The value may be a
+// path to a zoneinfo directory or zip file, an IANA release name such as
+// "2024a" installed as ~/.config/t/tzdata/2024a (directory) or 2024a.zip, or
+// "system" to restore the default lookup.
+func Use(spec string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if spec == "" || spec == "system" {
+		return reset()
+	}
+
+	path, err := resolve(spec)
+	if err != nil {
+		return err
+	}
+
+	src, err := newSource(path)
+	if err != nil {
+		return err
+	}
+
+	if err := reset(); err != nil {
+		return err
+	}
+	current = src
+	return nil
+}
+
+// reset closes any selected source and clears loaded locations.
+// The caller must hold mu.
+func reset() error {
+	var err error
+	if current != nil {
+		err = current.close()
+	}
+	current = nil
+	cache = make(map[string]*time.Location)
+	preloaded = make(map[string][]byte)
+	return err
+}
+
+// newSource opens a zoneinfo directory or zip file.
+func newSource(path string) (*source, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening tzdata: %w", err)
+	}
+
+	src := &source{path: path}
+	if !info.IsDir() {
+		src.zip, err = zip.OpenReader(path)
+		if err != nil {
+			return nil, fmt.Errorf("opening tzdata zip %s: %w", path, err)
+		}
+	}
+	return src, nil
+}
+
+// close releases the source's zip file, if any.
+func (s *source) close() error {
+	if s.zip == nil {
+		return nil
+	}
+	return s.zip.Close()
+}
+
+// resolve turns a --tzdata value into a path. Existing paths are used as-is;
+// release names are looked up in the config directory.
+func resolve(spec string) (string, error) {
+	if _, err := os.Stat(spec); err == nil || !versionRegex.MatchString(spec) {
+		return spec, nil
+	}
+
+	configDir, err := config.DefaultConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(configDir, "tzdata")
+	for _, candidate := range []string{spec, spec + ".zip"} {
+		path := filepath.Join(dir, candidate)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("tzdata %s not found in %s (expected %s/ or %s.zip)", spec, dir, spec, spec)
+}
+
+// LoadLocation returns the location with the given IANA name from the
+// selected source, or from the time package's default lookup if none is.
+func LoadLocation(name string) (*time.Location, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if loc, ok := cache[name]; ok {
+		return loc, nil
+	}
+
+	if data, ok := preloaded[name]; ok {
+		delete(preloaded, name)
+		// Bad data falls back to the source, so a stale cache can't break lookups
+		if loc, err := time.LoadLocationFromTZData(name, data); err == nil {
+			cache[name] = loc
+			return loc, nil
+		}
+	}
+
+	var loc *time.Location
+	var err error
+	if current == nil {
+		loc, err = time.LoadLocation(name)
+	} else {
+		loc, err = current.load(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cache[name] = loc
+	return loc, nil
+}
+
+// load reads a zone file from the source.
+func (s *source) load(name string) (*time.Location, error) {
+	if name == "UTC" || name == "" {
+		return time.UTC, nil
+	}
+	if !validName(name) {
+		return nil, fmt.Errorf("invalid time zone name %q", name)
+	}
+
+	data, err := s.read(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("unknown time zone %s in %s", name, s.path)
+		}
+		return nil, err
+	}
+
+	return time.LoadLocationFromTZData(name, data)
+}
+
+// read returns the raw tzfile contents for a zone.
+func (s *source) read(name string) ([]byte, error) {
+	if s.zip == nil {
+		return os.ReadFile(filepath.Join(s.path, filepath.FromSlash(name)))
+	}
+
+	f, err := s.zip.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	return io.ReadAll(f)
+}
+
+// validName reports whether name is safe to use as a relative path,
+// mirroring the checks time.LoadLocation makes.
+func validName(name string) bool {
+	if strings.HasPrefix(name, "/") || strings.Contains(name, `\`) {
+		return false
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." || part == "." || part == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// systemZoneDirs are the directories the time package searches for zone
+// files on Unix-like systems, after ZONEINFO.
+var systemZoneDirs = []string{
+	"/usr/share/zoneinfo/",
+	"/usr/share/lib/zoneinfo/",
+	"/usr/lib/locale/TZ/",
+	"/etc/zoneinfo/",
+}
+
+// ZoneData returns the raw tzfile contents for a zone from the selected
+// source, or else from ZONEINFO or the system zoneinfo directories. It can't
+// read the copy embedded in the binary.
+func ZoneData(name string) ([]byte, error) {
+	if !validName(name) {
+		return nil, fmt.Errorf("invalid time zone name %q", name)
+	}
+
+	mu.Lock()
+	if current != nil {
+		defer mu.Unlock()
+		return current.read(name)
+	}
+	mu.Unlock()
+
+	var paths []string
+	if zoneinfo := os.Getenv("ZONEINFO"); zoneinfo != "" {
+		paths = append(paths, zoneinfo)
+	}
+	paths = append(paths, systemZoneDirs...)
+
+	for _, path := range paths {
+		src, err := newSource(path)
+		if err != nil {
+			continue
+		}
+		data, err := src.read(name)
+		_ = src.close()
+		if err == nil {
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown time zone %s: %w", name, os.ErrNotExist)
+}
+
+// Preload makes LoadLocation parse locations from tzfile data instead of
+// reading them, e.g. from a cache. Data is only parsed for the locations
+// that are loaded. Use clears preloaded data.
+func Preload(zones map[string][]byte) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for name, data := range zones {
+		preloaded[name] = data
+	}
+}
+
+// Loaded returns the names of the locations loaded so far.
+func Loaded() []string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	names := make([]string, 0, len(cache))
+	for name := range cache {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,194 @@
+package tzdata
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// systemZone reads a zone file from the system zoneinfo for use as fixture data.
+func systemZone(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("/usr/share/zoneinfo", name))
+	if err != nil {
+		t.Skipf("system zoneinfo not available: %v", err)
+	}
+	return data
+}
+
+// useTZData selects spec for the duration of the test.
+func useTZData(t *testing.T, spec string) {
+	t.Helper()
+	require.NoError(t, Use(spec))
+	t.Cleanup(func() { _ = Use("system") })
+}
+
+// writeZoneDir creates a zoneinfo directory holding only the given zones.
+func writeZoneDir(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, systemZone(t, name), 0o644))
+	}
+	return dir
+}
+
+// writeZoneZip creates a zoneinfo zip holding only the given zones.
+func writeZoneZip(t *testing.T, path string, names ...string) {
+	t.Helper()
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for _, name := range names {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(systemZone(t, name))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+}
+
+func TestLoadLocation_Default(t *testing.T) {
+	loc, err := LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", loc.String())
+
+	_, err = LoadLocation("Not/AZone")
+	assert.Error(t, err)
+}
+
+func TestUse_Directory(t *testing.T) {
+	useTZData(t, writeZoneDir(t, "Asia/Tokyo"))
+
+	loc, err := LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", loc.String())
+
+	_, err = LoadLocation("Europe/London")
+	assert.ErrorContains(t, err, "unknown time zone Europe/London")
+
+	loc, err = LoadLocation("UTC")
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", loc.String())
+}
+
+func TestUse_Zip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zoneinfo.zip")
+	writeZoneZip(t, path, "Europe/London")
+	useTZData(t, path)
+
+	loc, err := LoadLocation("Europe/London")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/London", loc.String())
+
+	_, err = LoadLocation("Asia/Tokyo")
+	assert.Error(t, err)
+}
+
+func TestUse_Version(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tzdataDir := filepath.Join(home, ".config", "t", "tzdata")
+	require.NoError(t, os.MkdirAll(tzdataDir, 0o755))
+	writeZoneZip(t, filepath.Join(tzdataDir, "2024a.zip"), "Asia/Kolkata")
+
+	useTZData(t, "2024a")
+	_, err := LoadLocation("Asia/Kolkata")
+	assert.NoError(t, err)
+
+	err = Use("1999z")
+	assert.ErrorContains(t, err, "tzdata 1999z not found")
+}
+
+func TestUse_System(t *testing.T) {
+	useTZData(t, writeZoneDir(t, "Asia/Tokyo"))
+	_, err := LoadLocation("Europe/London")
+	require.Error(t, err)
+
+	require.NoError(t, Use("system"))
+	_, err = LoadLocation("Europe/London")
+	assert.NoError(t, err, "system lookup should be restored")
+}
+
+func TestUse_Invalid(t *testing.T) {
+	assert.Error(t, Use(filepath.Join(t.TempDir(), "missing")))
+
+	notZip := filepath.Join(t.TempDir(), "zoneinfo.zip")
+	require.NoError(t, os.WriteFile(notZip, []byte("not a zip"), 0o644))
+	assert.Error(t, Use(notZip))
+}
+
+func TestValidName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Asia/Tokyo", true},
+		{"America/Argentina/Buenos_Aires", true},
+		{"UTC", true},
+		{"../etc/passwd", false},
+		{"/etc/passwd", false},
+		{"Asia/../../etc", false},
+		{"Asia//Tokyo", false},
+		{`Asia\Tokyo`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, validName(tt.name))
+		})
+	}
+}
+
+func TestZoneData(t *testing.T) {
+	want := systemZone(t, "Asia/Tokyo")
+
+	data, err := ZoneData("Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, want, data)
+
+	t.Setenv("ZONEINFO", writeZoneDir(t, "Europe/London"))
+	data, err = ZoneData("Europe/London")
+	require.NoError(t, err)
+	assert.Equal(t, systemZone(t, "Europe/London"), data)
+
+	useTZData(t, writeZoneDir(t, "Asia/Tokyo"))
+	data, err = ZoneData("Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, want, data)
+	_, err = ZoneData("Europe/London")
+	assert.Error(t, err)
+
+	_, err = ZoneData("../etc/passwd")
+	assert.Error(t, err)
+}
+
+func TestPreload(t *testing.T) {
+	useTZData(t, writeZoneDir(t))
+
+	// The empty source has no zones, so only preloaded ones load
+	_, err := LoadLocation("Asia/Tokyo")
+	require.Error(t, err)
+
+	Preload(map[string][]byte{
+		"Asia/Tokyo":    systemZone(t, "Asia/Tokyo"),
+		"Europe/Paris":  systemZone(t, "Europe/Paris"),
+		"Asia/Calcutta": []byte("not a tzfile"),
+	})
+	loc, err := LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", loc.String())
+	assert.Equal(t, []string{"Asia/Tokyo"}, Loaded(), "preloaded zones aren't parsed until loaded")
+
+	// Bad data falls back to the source, which lacks the zone
+	_, err = LoadLocation("Asia/Calcutta")
+	assert.Error(t, err)
+}